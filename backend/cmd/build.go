@@ -20,6 +20,8 @@ var (
 	buildFetch    bool
 	buildCacheDir string
 	buildCacheTTL time.Duration
+	buildTZ       string
+	buildPosters  string
 )
 
 var buildCmd = &cobra.Command{
@@ -30,7 +32,12 @@ var buildCmd = &cobra.Command{
 By default, it uses locally cached metadata only (no network).
 Use --fetch to retrieve metadata from external APIs and update the cache.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		recs, err := csvio.ReadNetflixCSV(buildIn)
+		loc, err := loadLocation(buildTZ)
+		if err != nil {
+			return err
+		}
+
+		recs, err := csvio.ReadNetflixCSVWithOptions(buildIn, csvio.Options{Location: loc})
 		if err != nil {
 			return err
 		}
@@ -66,6 +73,10 @@ Use --fetch to retrieve metadata from external APIs and update the cache.`,
 			return err
 		}
 
+		if buildPosters != "" && buildFetch {
+			downloadPosters(outStruct.Items, buildPosters)
+		}
+
 		if flagVerbose {
 			fmt.Fprintf(os.Stderr, "wrote %s\n", buildOut)
 			fmt.Fprintf(os.Stderr, "cache hits=%d misses=%d fetched=%d unresolved=%d\n",
@@ -84,6 +95,44 @@ func init() {
 	buildCmd.Flags().BoolVar(&buildFetch, "fetch", false, "fetch metadata from external APIs before building")
 	buildCmd.Flags().StringVar(&buildCacheDir, "cache-dir", store.DefaultCacheDir(), "metadata cache directory")
 	buildCmd.Flags().DurationVar(&buildCacheTTL, "cache-ttl", 72*time.Hour, "cache expiration duration")
+	buildCmd.Flags().StringVar(&buildPosters, "poster-dir", "", "with --fetch, download poster images into this directory (for recap --format html)")
+	buildCmd.Flags().StringVar(&buildTZ, "tz", "", "time zone for start times of the account export, e.g. Asia/Tokyo (default: local)")
 
 	_ = buildCmd.MarkFlagRequired("in")
 }
+
+// downloadPosters saves the poster of every resolved work into dir.
+// Failures are reported but do not fail the build.
+func downloadPosters(items []build.BuiltItem, dir string) {
+	seen := make(map[string]bool)
+	downloaded := 0
+	for _, it := range items {
+		if it.Metadata == nil || it.Metadata.PosterPath == "" || seen[it.Metadata.PosterPath] {
+			continue
+		}
+		seen[it.Metadata.PosterPath] = true
+		ok, err := tmdbprovider.DownloadPoster(it.Metadata.PosterPath, dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "poster %s: %v\n", it.Metadata.PosterPath, err)
+			continue
+		}
+		if ok {
+			downloaded++
+		}
+	}
+	if flagVerbose {
+		fmt.Fprintf(os.Stderr, "downloaded %d posters into %s\n", downloaded, dir)
+	}
+}
+
+// loadLocation resolves a time zone name, using the local zone for "".
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %w", name, err)
+	}
+	return loc, nil
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/diff"
+)
+
+var (
+	diffOut      string
+	diffYear     int
+	diffFrom     string
+	diffTo       string
+	diffFormat   string
+	diffExitCode bool
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff OLD.json NEW.json",
+	Short: "Show what changed between two built JSON files of the same history",
+	Long: `Compares two builds of the same viewing history, e.g. before and after
+changing the title normalization or refreshing the metadata cache. Reports
+views whose normalized title, type or metadata changed, works that became
+resolved or unresolved, and the effect on the headline stats.`,
+	Example: `  nfrecap build --in NetflixViewingHistory.csv --out new.json --fetch
+  nfrecap diff old.json new.json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if diffFormat != formatMarkdown && diffFormat != formatJSON {
+			return fmt.Errorf("invalid format %q: must be %s or %s", diffFormat, formatMarkdown, formatJSON)
+		}
+		var opts diff.Options
+		if diffYear != 0 || diffFrom != "" || diffTo != "" {
+			period, err := recapPeriod(diffYear, diffFrom, diffTo)
+			if err != nil {
+				return err
+			}
+			opts.Period = period
+		}
+
+		oldBuilt, err := build.ReadJSON(args[0])
+		if err != nil {
+			return err
+		}
+		newBuilt, err := build.ReadJSON(args[1])
+		if err != nil {
+			return err
+		}
+		result := diff.Compare(oldBuilt, newBuilt, opts)
+
+		var buf bytes.Buffer
+		if diffFormat == formatJSON {
+			b, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				return err
+			}
+			buf.Write(append(b, '\n'))
+		} else if err := diff.WriteMarkdown(&buf, result); err != nil {
+			return err
+		}
+		if diffOut == "-" {
+			if _, err := os.Stdout.Write(buf.Bytes()); err != nil {
+				return err
+			}
+		} else if err := os.WriteFile(diffOut, buf.Bytes(), 0644); err != nil {
+			return err
+		}
+
+		if diffExitCode && !result.IsZero() {
+			os.Exit(1) // like `git diff --exit-code`, without an error message
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().StringVarP(&diffOut, "out", "o", "-", "output file ('-' for stdout)")
+	diffCmd.Flags().IntVarP(&diffYear, "year", "y", 0, "year of the headline stats (default: every day of both files)")
+	diffCmd.Flags().StringVar(&diffFrom, "from", "", "start date of the headline stats (YYYY-MM-DD)")
+	diffCmd.Flags().StringVar(&diffTo, "to", "", "end date of the headline stats (YYYY-MM-DD, default: today)")
+	diffCmd.Flags().StringVar(&diffFormat, "format", formatMarkdown, "output format: markdown or json")
+	diffCmd.Flags().BoolVar(&diffExitCode, "exit-code", false, "exit with status 1 if the files differ")
+
+	diffCmd.MarkFlagsMutuallyExclusive("year", "from")
+	diffCmd.MarkFlagsMutuallyExclusive("year", "to")
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/kmdkuk/nfrecap/internal/ics"
+	"github.com/kmdkuk/nfrecap/internal/recap"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the viewing history of built JSON to other formats",
+}
+
+var (
+	icsIn    string
+	icsOut   string
+	icsYear  int
+	icsFrom  string
+	icsTo    string
+	icsGroup string
+	icsLang  string
+)
+
+var exportICSCmd = &cobra.Command{
+	Use:   "ics",
+	Short: "Export the viewing history as an iCalendar (.ics) file of all-day events",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := ics.Options{Group: icsGroup, Lang: icsLang}
+		if opts.Lang == "" {
+			opts.Lang = viper.GetString("recap.lang")
+		}
+		if icsYear != 0 || icsFrom != "" || icsTo != "" {
+			period, err := recapPeriod(icsYear, icsFrom, icsTo)
+			if err != nil {
+				return err
+			}
+			opts.Period = period
+		}
+		if err := opts.Validate(); err != nil {
+			return err
+		}
+
+		built, err := recap.ReadBuiltJSON(icsIn)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := ics.Write(&buf, built, opts); err != nil {
+			return err
+		}
+		if icsOut == "-" {
+			_, err := os.Stdout.Write(buf.Bytes())
+			return err
+		}
+		return os.WriteFile(icsOut, buf.Bytes(), 0644)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.AddCommand(exportICSCmd)
+
+	exportICSCmd.Flags().StringVarP(&icsIn, "in", "i", "", "input built JSON file (from nfrecap build)")
+	exportICSCmd.Flags().StringVarP(&icsOut, "out", "o", "-", "output .ics file ('-' for stdout)")
+	exportICSCmd.Flags().IntVarP(&icsYear, "year", "y", 0, "only views in this year (default: every view)")
+	exportICSCmd.Flags().StringVar(&icsFrom, "from", "", "only views from this date (YYYY-MM-DD)")
+	exportICSCmd.Flags().StringVar(&icsTo, "to", "", "only views until this date (YYYY-MM-DD, default: today)")
+	exportICSCmd.Flags().StringVar(&icsGroup, "group", ics.GroupDay, "one event per title per day, or per binge of consecutive days: day or binge")
+	exportICSCmd.Flags().StringVar(&icsLang, "lang", "", "language of event text: ja or en (default: the recap lang setting, else ja)")
+
+	_ = exportICSCmd.MarkFlagRequired("in")
+	exportICSCmd.MarkFlagsMutuallyExclusive("year", "from")
+	exportICSCmd.MarkFlagsMutuallyExclusive("year", "to")
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+)
+
+var (
+	migrateIn     string
+	migrateOut    string
+	migrateSchema bool
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade a built JSON file to the current schema version",
+	Long: fmt.Sprintf(`Upgrades a file written by an older `+"`nfrecap build`"+` to schema version %d,
+and checks it against the schema. Other commands read older files too, but
+migrate them in memory on every run.`, build.SchemaVersion),
+	Example: `  nfrecap migrate --in NetflixViewingHistory.json --out NetflixViewingHistory.json
+
+  # The JSON Schema of the current version
+  nfrecap migrate --schema`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if migrateSchema {
+			_, err := os.Stdout.Write(build.JSONSchema)
+			return err
+		}
+		if migrateIn == "" {
+			return errors.New(`required flag(s) "in" not set`)
+		}
+
+		built, from, err := build.ReadJSONVersion(migrateIn)
+		if err != nil {
+			return err
+		}
+		out, err := json.MarshalIndent(built, "", "  ")
+		if err != nil {
+			return err
+		}
+		out = append(out, '\n')
+
+		if migrateOut == "-" {
+			if _, err := os.Stdout.Write(out); err != nil {
+				return err
+			}
+		} else if err := os.WriteFile(migrateOut, out, 0644); err != nil {
+			return err
+		}
+		if from == build.SchemaVersion {
+			fmt.Fprintf(os.Stderr, "%s is already at schema version %d\n", migrateIn, from)
+		} else {
+			fmt.Fprintf(os.Stderr, "migrated %s from schema version %d to %d\n", migrateIn, from, build.SchemaVersion)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+
+	migrateCmd.Flags().StringVarP(&migrateIn, "in", "i", "", "built JSON file to upgrade")
+	migrateCmd.Flags().StringVarP(&migrateOut, "out", "o", "-", "output file ('-' for stdout); may be the input file")
+	migrateCmd.Flags().BoolVar(&migrateSchema, "schema", false, "print the JSON Schema of the current version and exit")
+}
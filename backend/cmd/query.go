@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/i18n"
+	"github.com/kmdkuk/nfrecap/internal/query"
+)
+
+var (
+	queryIn         string
+	queryOut        string
+	queryYear       int
+	queryFrom       string
+	queryTo         string
+	queryTitleRegex string
+	queryFilter     build.Filter
+	queryFormat     string
+	queryLang       string
+)
+
+// Output formats of `nfrecap query`.
+const formatTable = "table"
+
+var queryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "List the views of built JSON that match filters",
+	Example: `  # Horror movies watched in October 2025
+  nfrecap query --in built.json --from 2025-10-01 --to 2025-10-31 --type movie --genre horror
+
+  # Every episode of a series, as CSV
+  nfrecap query --in built.json --title "Stranger Things" --format csv`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch queryFormat {
+		case formatTable, formatJSON, formatCSV:
+		default:
+			return fmt.Errorf("invalid format %q: must be one of %s, %s or %s", queryFormat, formatTable, formatJSON, formatCSV)
+		}
+		lang := queryLanguage()
+		if _, err := i18n.New(lang); err != nil {
+			return err
+		}
+		f := queryFilter
+		if queryYear != 0 || queryFrom != "" || queryTo != "" {
+			period, err := recapPeriod(queryYear, queryFrom, queryTo)
+			if err != nil {
+				return err
+			}
+			f.From, f.To = period.From, period.To
+		}
+		if queryTitleRegex != "" {
+			var err error
+			if f.TitleExpr, err = regexp.Compile("(?i)" + queryTitleRegex); err != nil {
+				return fmt.Errorf("invalid --title-regex: %w", err)
+			}
+		}
+		if err := f.Validate(); err != nil {
+			return err
+		}
+
+		built, err := build.ReadJSON(queryIn)
+		if err != nil {
+			return err
+		}
+		if err := f.CheckGenres(built); err != nil {
+			return err
+		}
+		built = f.Apply(built)
+
+		var buf bytes.Buffer
+		switch queryFormat {
+		case formatJSON:
+			b, err := json.MarshalIndent(built, "", "  ")
+			if err != nil {
+				return err
+			}
+			buf.Write(append(b, '\n'))
+		case formatCSV:
+			err = query.WriteCSV(&buf, query.Rows(built, lang))
+		default:
+			err = query.WriteTable(&buf, query.Rows(built, lang))
+		}
+		if err != nil {
+			return err
+		}
+		if queryOut == "-" {
+			_, err := os.Stdout.Write(buf.Bytes())
+			return err
+		}
+		return os.WriteFile(queryOut, buf.Bytes(), 0644)
+	},
+}
+
+// queryLanguage is the language of genre names: --lang, else the recap
+// setting.
+func queryLanguage() string {
+	if queryLang != "" {
+		return queryLang
+	}
+	return viper.GetString("recap.lang")
+}
+
+func init() {
+	rootCmd.AddCommand(queryCmd)
+
+	queryCmd.Flags().StringVarP(&queryIn, "in", "i", "", "input built JSON file (from nfrecap build)")
+	queryCmd.Flags().StringVarP(&queryOut, "out", "o", "-", "output file ('-' for stdout)")
+	queryCmd.Flags().IntVarP(&queryYear, "year", "y", 0, "only views in this year")
+	queryCmd.Flags().StringVar(&queryFrom, "from", "", "only views from this date (YYYY-MM-DD)")
+	queryCmd.Flags().StringVar(&queryTo, "to", "", "only views until this date (YYYY-MM-DD, default: today); requires --from")
+	queryCmd.Flags().StringSliceVar(&queryFilter.Types, "type", nil, "only these types: movie, tv or unknown")
+	queryCmd.Flags().StringSliceVar(&queryFilter.Genres, "genre", nil, "only these genres, by key or name (e.g. horror, ホラー)")
+	queryCmd.Flags().StringVar(&queryFilter.Title, "title", "", "only work titles containing this text (case-insensitive)")
+	queryCmd.Flags().StringVar(&queryTitleRegex, "title-regex", "", "only work titles matching this regular expression (case-insensitive)")
+	queryCmd.Flags().StringSliceVar(&queryFilter.Providers, "provider", nil, "only views with metadata from these providers, e.g. tmdb")
+	queryCmd.Flags().StringVar(&queryFilter.Status, "status", "", "only resolved or unresolved views (default: both)")
+	queryCmd.Flags().StringVar(&queryFormat, "format", formatTable, "output format: table, json (built JSON) or csv")
+	queryCmd.Flags().StringVar(&queryLang, "lang", "", "language of genre names: ja or en (default: the recap lang setting, else ja)")
+
+	_ = queryCmd.MarkFlagRequired("in")
+	queryCmd.MarkFlagsMutuallyExclusive("year", "from")
+	queryCmd.MarkFlagsMutuallyExclusive("year", "to")
+}
@@ -3,26 +3,83 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
+	"github.com/kmdkuk/nfrecap/internal/build"
 	"github.com/kmdkuk/nfrecap/internal/recap"
 )
 
 var (
-	recapIn   string
-	recapOut  string
-	recapYear int
+	recapIn      string
+	recapOut     string
+	recapYear    int
+	recapFrom    string
+	recapTo      string
+	recapCompare int
+	recapAllTime bool
+	recapHoliday string
+	recapReport  reportOverrides
+	recapFormat  string
+	recapPosters string
+	recapTmpl    string
+	recapMermaid bool
 )
 
+// Report formats of `nfrecap recap`.
+const (
+	formatMarkdown = "markdown"
+	formatHTML     = "html"
+	formatJSON     = "json"
+	formatCSV      = "csv"
+	formatPNG      = "png"
+)
+
+// reportOverrides are report settings given on the command line or in an
+// API request, applied on top of the "recap" config section.
+type reportOverrides struct {
+	SortBy       string
+	Limits       map[string]int
+	Sections     []string // only these sections, if set
+	SkipSections []string
+	Appendix     bool   // add the opt-in unresolved appendix
+	Lang         string // report language
+
+	// Filter restricts the views the report covers; ExcludeTitlesFile adds
+	// the titles of a list file to Filter.ExcludeTitles.
+	Filter            build.Filter
+	ExcludeTitlesFile string
+}
+
 var recapCmd = &cobra.Command{
 	Use:   "recap",
-	Short: "Generate stats-heavy recap markdown from built JSON",
+	Short: "Generate a stats-heavy recap report (markdown or html) from built JSON",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		year := recapYear
-		if year == 0 {
-			year = time.Now().Year()
+		switch recapFormat {
+		case formatMarkdown, formatHTML, formatJSON:
+		case formatCSV, formatPNG:
+			if recapOut == "-" {
+				return fmt.Errorf("--format %s requires --out <directory>", recapFormat)
+			}
+		default:
+			return fmt.Errorf("invalid format %q: must be one of %s, %s, %s, %s or %s", recapFormat, formatMarkdown, formatHTML, formatJSON, formatCSV, formatPNG)
+		}
+		var tmpl string
+		if recapTmpl != "" {
+			if recapFormat != formatMarkdown && recapFormat != formatHTML {
+				return fmt.Errorf("--template requires --format %s or %s", formatMarkdown, formatHTML)
+			}
+			b, err := os.ReadFile(recapTmpl)
+			if err != nil {
+				return fmt.Errorf("failed to read template: %w", err)
+			}
+			tmpl = string(b)
+		}
+		if recapMermaid && recapFormat != formatMarkdown {
+			return fmt.Errorf("--mermaid requires --format %s", formatMarkdown)
 		}
 
 		built, err := recap.ReadBuiltJSON(recapIn)
@@ -30,23 +87,243 @@ var recapCmd = &cobra.Command{
 			return err
 		}
 
-		stats := recap.ComputeStats(built, year)
-		md := recap.RenderMarkdown(stats)
+		if recapAllTime {
+			if recapFormat != formatMarkdown || tmpl != "" || recapMermaid {
+				return fmt.Errorf("--all-time supports only the built-in %s report", formatMarkdown)
+			}
+			opts, err := recapOptions(built, recap.Period{}, recapHoliday, recapReport)
+			if err != nil {
+				return err
+			}
+			out, err := recap.RenderAllTimeMarkdown(recap.ComputeAllTimeWithOptions(built, opts))
+			if err != nil {
+				return err
+			}
+			return writeRecap(out)
+		}
 
-		if recapOut == "-" {
-			fmt.Print(md)
-			return nil
+		period, err := recapPeriod(recapYear, recapFrom, recapTo)
+		if err != nil {
+			return err
 		}
-		return os.WriteFile(recapOut, []byte(md), 0644)
+
+		opts, err := recapOptions(built, period, recapHoliday, recapReport)
+		if err != nil {
+			return err
+		}
+		stats := recap.ComputeStatsWithOptions(built, opts)
+		if recapCompare != 0 {
+			opts.Period = recap.YearPeriod(recapCompare)
+			base := recap.ComputeStatsWithOptions(built, opts)
+			diff := recap.Compare(stats, base)
+			stats.Comparison = &diff
+		}
+		var out string
+		switch recapFormat {
+		case formatHTML:
+			if tmpl == "" {
+				tmpl = recap.BuiltinTemplate(recap.HTMLTemplateFile)
+			}
+			out, err = recap.RenderHTMLTemplate(stats, recap.HTMLOptions{PosterDir: recapPosters}, tmpl)
+		case formatJSON:
+			b, err := recap.MarshalExportJSON(stats)
+			if err != nil {
+				return err
+			}
+			return writeRecap(string(b))
+		case formatCSV:
+			return recap.WriteExportCSV(stats, recapOut)
+		case formatPNG:
+			return recap.WriteCardPNGs(stats, recapOut)
+		default:
+			if tmpl == "" {
+				tmpl = recap.BuiltinTemplate(recap.MarkdownTemplateFile)
+			}
+			out, err = recap.RenderMarkdownTemplateWithOptions(stats, recap.MarkdownOptions{Mermaid: recapMermaid}, tmpl)
+		}
+		if err != nil {
+			return err
+		}
+		return writeRecap(out)
 	},
 }
 
+func writeRecap(md string) error {
+	if recapOut == "-" {
+		fmt.Print(md)
+		return nil
+	}
+	return os.WriteFile(recapOut, []byte(md), 0644)
+}
+
 func init() {
 	rootCmd.AddCommand(recapCmd)
 
-	recapCmd.Flags().StringVarP(&recapIn, "in", "i", "", "input built JSON file (from `nfrecap build`)")
-	recapCmd.Flags().StringVarP(&recapOut, "out", "o", "-", "output markdown file ('-' for stdout)")
+	recapCmd.Flags().StringVarP(&recapIn, "in", "i", "", "input built JSON file (from nfrecap build)")
+	recapCmd.Flags().StringVarP(&recapOut, "out", "o", "-", "output file ('-' for stdout), or directory for --format csv and png")
 	recapCmd.Flags().IntVarP(&recapYear, "year", "y", 0, "target year (default: current year)")
+	recapCmd.Flags().StringVar(&recapFrom, "from", "", "start date of a custom range (YYYY-MM-DD), instead of --year")
+	recapCmd.Flags().StringVar(&recapTo, "to", "", "end date of a custom range (YYYY-MM-DD, default: today)")
+	recapCmd.Flags().IntVar(&recapCompare, "compare", 0, "year to compare against (e.g. previous year); only with --year")
+	recapCmd.Flags().BoolVar(&recapAllTime, "all-time", false, "summarize every year in the built JSON")
+	recapCmd.Flags().StringVar(&recapHoliday, "holidays", "", "holiday calendar file (YAML/JSON with a \"holidays\" list)")
+	recapCmd.Flags().StringVar(&recapReport.SortBy, "sort", "", "ranking sort key: duration or views (default: duration)")
+	recapCmd.Flags().StringToIntVar(&recapReport.Limits, "limit", nil, "ranking sizes, e.g. titles_shown=20,genres=5")
+	recapCmd.Flags().StringSliceVar(&recapReport.Sections, "sections", nil, "only include these report sections")
+	recapCmd.Flags().StringSliceVar(&recapReport.SkipSections, "skip-sections", nil, "report sections to leave out")
+	recapCmd.Flags().StringVar(&recapReport.Lang, "lang", "", "report language: ja or en (default: ja)")
+	recapCmd.Flags().StringVar(&recapFormat, "format", formatMarkdown, "output format: markdown, html, json, csv or png (share cards)")
+	recapCmd.Flags().BoolVar(&recapMermaid, "mermaid", false, "add Mermaid charts to the markdown report")
+	recapCmd.Flags().StringVar(&recapTmpl, "template", "", "custom report template file (text/template for markdown, html/template for html)")
+	recapCmd.Flags().StringVar(&recapPosters, "poster-dir", "", "directory of poster images to embed in the html report (see build --poster-dir)")
+	recapCmd.Flags().BoolVar(&recapReport.Appendix, "unresolved-appendix", false, "append every unresolved work with its raw titles")
+	recapCmd.Flags().StringSliceVar(&recapReport.Filter.Types, "type", nil, "only count these types: movie, tv or unknown")
+	recapCmd.Flags().StringSliceVar(&recapReport.Filter.Genres, "genre", nil, "only count these genres, by key or name (e.g. animation, アニメーション)")
+	recapCmd.Flags().StringVar(&recapReport.ExcludeTitlesFile, "exclude-titles", "", "file of work titles to leave out, one per line")
 
 	_ = recapCmd.MarkFlagRequired("in")
+	recapCmd.MarkFlagsMutuallyExclusive("year", "from")
+	recapCmd.MarkFlagsMutuallyExclusive("year", "to")
+	recapCmd.MarkFlagsMutuallyExclusive("all-time", "year")
+	recapCmd.MarkFlagsMutuallyExclusive("all-time", "from")
+	recapCmd.MarkFlagsMutuallyExclusive("all-time", "to")
+	recapCmd.MarkFlagsMutuallyExclusive("all-time", "compare")
+	recapCmd.MarkFlagsMutuallyExclusive("compare", "from") // the comparison is by calendar year
+	recapCmd.MarkFlagsMutuallyExclusive("compare", "to")
+}
+
+// recapPeriod resolves --year / --from / --to into a period.
+// A calendar year is used unless a custom range is given.
+func recapPeriod(year int, from, to string) (recap.Period, error) {
+	if from == "" && to != "" {
+		return recap.Period{}, fmt.Errorf("--to requires --from")
+	}
+	if from != "" {
+		return recap.ParsePeriod(from, to)
+	}
+	if year == 0 {
+		year = time.Now().Year()
+	}
+	return recap.YearPeriod(year), nil
+}
+
+// recapOptions builds stats options for period from the config file.
+// Holidays come from holidayFile if given, else from the config file. The
+// genres of the filter are checked against built.
+func recapOptions(built build.Built, period recap.Period, holidayFile string, report reportOverrides) (recap.Options, error) {
+	holidays, err := loadHolidays(holidayFile)
+	if err != nil {
+		return recap.Options{}, err
+	}
+	cfg, err := loadReportConfig(report)
+	if err != nil {
+		return recap.Options{}, err
+	}
+	filter := report.Filter
+	if report.ExcludeTitlesFile != "" {
+		titles, err := build.ReadTitleList(report.ExcludeTitlesFile)
+		if err != nil {
+			return recap.Options{}, err
+		}
+		filter.ExcludeTitles = append(slices.Clip(filter.ExcludeTitles), titles...)
+	}
+	if err := filter.Validate(); err != nil {
+		return recap.Options{}, err
+	}
+	if err := filter.CheckGenres(built); err != nil {
+		return recap.Options{}, err
+	}
+	devices, err := deviceRules()
+	if err != nil {
+		return recap.Options{}, err
+	}
+	return recap.Options{
+		Period:      period,
+		DeviceRules: devices,
+		Holidays:    holidays,
+		Config:      cfg,
+		Filter:      filter,
+	}, nil
+}
+
+// loadReportConfig reads the "recap" config section and applies overrides.
+func loadReportConfig(o reportOverrides) (recap.Config, error) {
+	var cfg recap.Config
+	if err := viper.UnmarshalKey("recap", &cfg); err != nil {
+		return recap.Config{}, fmt.Errorf("invalid recap config: %w", err)
+	}
+	// A zero limit in the file would silently mean the default.
+	for name := range viper.GetStringMap("recap.limits") {
+		if err := cfg.Limits.Set(name, viper.GetInt("recap.limits."+name)); err != nil {
+			return recap.Config{}, fmt.Errorf("invalid recap config: %w", err)
+		}
+	}
+	if o.SortBy != "" {
+		cfg.SortBy = o.SortBy
+	}
+	if o.Lang != "" {
+		cfg.Lang = o.Lang
+	}
+	for name, v := range o.Limits {
+		if err := cfg.Limits.Set(name, v); err != nil {
+			return recap.Config{}, err
+		}
+	}
+	if len(o.Sections) > 0 || len(o.SkipSections) > 0 || o.Appendix {
+		if cfg.Sections == nil {
+			cfg.Sections = make(map[string]bool)
+		}
+		if len(o.Sections) > 0 {
+			for _, name := range recap.Sections {
+				cfg.Sections[name] = false
+			}
+		}
+		for _, name := range o.Sections {
+			cfg.Sections[name] = true
+		}
+		for _, name := range o.SkipSections {
+			cfg.Sections[name] = false
+		}
+		if o.Appendix {
+			cfg.Sections[recap.SectionUnresolvedAppendix] = true
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return recap.Config{}, err
+	}
+	return cfg, nil
+}
+
+// loadHolidays reads the `holidays` list from path, or from the config file
+// when path is empty. It returns nil (built-in holidays) if none is set.
+func loadHolidays(path string) ([]recap.Holiday, error) {
+	v := viper.GetViper()
+	if path != "" {
+		v = viper.New()
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read holidays file: %w", err)
+		}
+	}
+	if !v.IsSet("holidays") {
+		return nil, nil
+	}
+	var holidays []recap.Holiday
+	if err := v.UnmarshalKey("holidays", &holidays); err != nil {
+		return nil, fmt.Errorf("invalid holidays: %w", err)
+	}
+	if err := recap.ValidateHolidays(holidays); err != nil {
+		return nil, err
+	}
+	return holidays, nil
+}
+
+// deviceRules returns the device rules from the "devices" config section,
+// tried before the built-in rules.
+func deviceRules() ([]recap.DeviceRule, error) {
+	var custom []recap.DeviceRule
+	if err := viper.UnmarshalKey("devices", &custom); err != nil {
+		return nil, fmt.Errorf("invalid devices config: %w", err)
+	}
+	return append(custom, recap.DefaultDeviceRules...), nil
 }
@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/kmdkuk/nfrecap/internal/build"
 	"github.com/kmdkuk/nfrecap/internal/csvio"
+	"github.com/kmdkuk/nfrecap/internal/provider"
 	tmdbprovider "github.com/kmdkuk/nfrecap/internal/provider/tmdb"
 	"github.com/kmdkuk/nfrecap/internal/recap"
 	"github.com/kmdkuk/nfrecap/internal/store"
@@ -40,66 +43,48 @@ var serveCmd = &cobra.Command{
 
 		// Setup Handler
 		http.HandleFunc("/api/recap", func(w http.ResponseWriter, r *http.Request) {
-			if r.Method != http.MethodPost {
-				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			stats, ok := recapFromRequest(w, r, cache, p)
+			if !ok {
 				return
 			}
 
-			// Parse Multipart
-			// 10MB limit
-			if err := r.ParseMultipartForm(10 << 20); err != nil {
-				http.Error(w, "Failed to parse form", http.StatusBadRequest)
-				return
+			// Response
+			resp := map[string]interface{}{
+				"recap": stats.WithoutDisabledSections(),
 			}
-
-			file, _, err := r.FormFile("file")
-			if err != nil {
-				http.Error(w, "Missing file part", http.StatusBadRequest)
-				return
+			if stats.Config.Enabled(recap.SectionCalendar) {
+				resp["calendar"] = recap.NewCompactCalendar(stats)
 			}
-			defer file.Close()
 
-			// Parse CSV
-			records, err := csvio.ParseNetflixCSV(file)
-			if err != nil {
-				http.Error(w, fmt.Sprintf("Failed to parse CSV: %v", err), http.StatusBadRequest)
-				return
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(resp); err != nil {
+				log.Printf("Failed to encode response: %v", err)
 			}
+		})
 
-			// Build
-			opts := build.Options{
-				Fetch:   true, // Always fetch (or make it configurable via query param?)
-				Verbose: true, // Log to stdout/stderr
+		// Share card: the same request as /api/recap plus "size"
+		// (story or wide, default story); responds with a PNG.
+		http.HandleFunc("/api/recap/card", func(w http.ResponseWriter, r *http.Request) {
+			stats, ok := recapFromRequest(w, r, cache, p)
+			if !ok {
+				return
 			}
-
-			// Recap
-			yearVal := r.FormValue("year")
-			year := time.Now().Year()
-			if yearVal != "" {
-				_, err := fmt.Sscanf(yearVal, "%d", &year)
-				if err != nil {
-					http.Error(w, fmt.Sprintf("Invalid year: %v", err), http.StatusBadRequest)
+			size := recap.CardStory
+			if v := r.FormValue("size"); v != "" {
+				var err error
+				if size, err = recap.ParseCardSize(v); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
 					return
 				}
 			}
-
-			// Execute build process
-			builtData, _, err := build.Run(records, cache, p, opts)
+			b, err := recap.RenderCardPNG(stats, size)
 			if err != nil {
-				http.Error(w, fmt.Sprintf("Build run failed: %v", err), http.StatusInternalServerError)
+				http.Error(w, fmt.Sprintf("Failed to render card: %v", err), http.StatusInternalServerError)
 				return
 			}
-
-			stats := recap.ComputeStats(builtData, year)
-
-			// Response
-			resp := map[string]interface{}{
-				"recap": stats,
-			}
-
-			w.Header().Set("Content-Type", "application/json")
-			if err := json.NewEncoder(w).Encode(resp); err != nil {
-				log.Printf("Failed to encode response: %v", err)
+			w.Header().Set("Content-Type", "image/png")
+			if _, err := w.Write(b); err != nil {
+				log.Printf("Failed to write response: %v", err)
 			}
 		})
 
@@ -109,6 +94,164 @@ var serveCmd = &cobra.Command{
 	},
 }
 
+// recapFromRequest builds the recap of an API request: a multipart form with
+// the viewing history CSV as "file" and the period, comparison and report
+// settings as form fields. It writes the error response itself if it fails.
+func recapFromRequest(w http.ResponseWriter, r *http.Request, cache store.Cache, p provider.Provider) (recap.Stats, bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return recap.Stats{}, false
+	}
+
+	// Parse Multipart
+	// 10MB limit
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return recap.Stats{}, false
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Missing file part", http.StatusBadRequest)
+		return recap.Stats{}, false
+	}
+	defer file.Close()
+
+	loc, err := loadLocation(r.FormValue("tz"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return recap.Stats{}, false
+	}
+
+	// Parse CSV
+	records, err := csvio.ParseNetflixCSVWithOptions(file, csvio.Options{Location: loc})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to parse CSV: %v", err), http.StatusBadRequest)
+		return recap.Stats{}, false
+	}
+
+	// Build
+	opts := build.Options{
+		Fetch:   true, // Always fetch (or make it configurable via query param?)
+		Verbose: true, // Log to stdout/stderr
+	}
+
+	// Recap
+	yearVal := r.FormValue("year")
+	year := 0
+	if yearVal != "" {
+		_, err := fmt.Sscanf(yearVal, "%d", &year)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid year: %v", err), http.StatusBadRequest)
+			return recap.Stats{}, false
+		}
+	}
+	if yearVal != "" && (r.FormValue("from") != "" || r.FormValue("to") != "") {
+		http.Error(w, "Invalid period: year cannot be combined with from or to", http.StatusBadRequest)
+		return recap.Stats{}, false
+	}
+	period, err := recapPeriod(year, r.FormValue("from"), r.FormValue("to"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid period: %v", err), http.StatusBadRequest)
+		return recap.Stats{}, false
+	}
+	compareYear := 0
+	if v := r.FormValue("compare"); v != "" {
+		if _, err := fmt.Sscanf(v, "%d", &compareYear); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid compare year: %v", err), http.StatusBadRequest)
+			return recap.Stats{}, false
+		}
+		if r.FormValue("from") != "" || r.FormValue("to") != "" {
+			http.Error(w, "Invalid compare year: compare requires a calendar year, not from or to", http.StatusBadRequest)
+			return recap.Stats{}, false
+		}
+	}
+
+	// Check the report settings before the build, which fetches metadata
+	report, err := reportOverridesFromForm(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return recap.Stats{}, false
+	}
+	if _, err := loadReportConfig(report); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return recap.Stats{}, false
+	}
+	if err := report.Filter.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return recap.Stats{}, false
+	}
+
+	// Execute build process
+	builtData, _, err := build.Run(records, cache, p, opts)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Build run failed: %v", err), http.StatusInternalServerError)
+		return recap.Stats{}, false
+	}
+
+	recapOpts, err := recapOptions(builtData, period, "", report)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return recap.Stats{}, false
+	}
+	stats := recap.ComputeStatsWithOptions(builtData, recapOpts)
+	if compareYear != 0 {
+		recapOpts.Period = recap.YearPeriod(compareYear)
+		base := recap.ComputeStatsWithOptions(builtData, recapOpts)
+		diff := recap.Compare(stats, base)
+		stats.Comparison = &diff
+	}
+	return stats, true
+}
+
+// reportOverridesFromForm reads the report settings of an API request:
+// "sort", "limit" (e.g. "titles_shown=20,genres=5"), comma separated
+// "sections" / "skip_sections", "unresolved_appendix" and "lang", and the
+// filter: comma separated "type" / "genre" and repeated "exclude_title".
+func reportOverridesFromForm(r *http.Request) (reportOverrides, error) {
+	o := reportOverrides{
+		SortBy:       r.FormValue("sort"),
+		Lang:         r.FormValue("lang"),
+		Sections:     splitList(r.FormValue("sections")),
+		SkipSections: splitList(r.FormValue("skip_sections")),
+		Filter: build.Filter{
+			Types:  splitList(r.FormValue("type")),
+			Genres: splitList(r.FormValue("genre")),
+		},
+	}
+	o.Filter.ExcludeTitles = r.Form["exclude_title"] // parsed by FormValue; titles may contain commas
+	if v := r.FormValue("unresolved_appendix"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return reportOverrides{}, fmt.Errorf("invalid unresolved_appendix %q", v)
+		}
+		o.Appendix = b
+	}
+	for _, kv := range splitList(r.FormValue("limit")) {
+		name, val, ok := strings.Cut(kv, "=")
+		n, err := strconv.Atoi(strings.TrimSpace(val))
+		if !ok || err != nil {
+			return reportOverrides{}, fmt.Errorf("invalid limit %q: want name=number", kv)
+		}
+		if o.Limits == nil {
+			o.Limits = make(map[string]int)
+		}
+		o.Limits[strings.TrimSpace(name)] = n
+	}
+	return o, nil
+}
+
+// splitList splits a comma separated list, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
 func init() {
 	rootCmd.AddCommand(serveCmd)
 
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/kmdkuk/nfrecap/internal/recap"
+	"github.com/kmdkuk/nfrecap/internal/tui"
+)
+
+var (
+	tuiIn      string
+	tuiYear    int
+	tuiHoliday string
+	tuiReport  reportOverrides
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Explore a recap in an interactive terminal dashboard",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		built, err := recap.ReadBuiltJSON(tuiIn)
+		if err != nil {
+			return err
+		}
+		// Validate the settings once up front rather than on every year switch.
+		opts, err := recapOptions(built, recap.Period{}, tuiHoliday, tuiReport)
+		if err != nil {
+			return err
+		}
+		return tui.Run(tui.Options{
+			Years: recap.Years(opts.Filter.Apply(built)),
+			Year:  tuiYear,
+			Stats: func(year int) recap.Stats {
+				opts.Period = recap.YearPeriod(year)
+				return recap.ComputeStatsWithOptions(built, opts)
+			},
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+
+	tuiCmd.Flags().StringVarP(&tuiIn, "in", "i", "", "input built JSON file (from nfrecap build)")
+	tuiCmd.Flags().IntVarP(&tuiYear, "year", "y", 0, "year shown first (default: the latest year with views)")
+	tuiCmd.Flags().StringVar(&tuiHoliday, "holidays", "", "holiday calendar file (YAML/JSON with a \"holidays\" list)")
+	tuiCmd.Flags().StringVar(&tuiReport.SortBy, "sort", "", "ranking sort key: duration or views (default: duration)")
+	tuiCmd.Flags().StringToIntVar(&tuiReport.Limits, "limit", nil, "ranking sizes, e.g. titles=100")
+	tuiCmd.Flags().StringVar(&tuiReport.Lang, "lang", "", "display language: ja or en (default: ja)")
+	tuiCmd.Flags().StringSliceVar(&tuiReport.Filter.Types, "type", nil, "only count these types: movie, tv or unknown")
+	tuiCmd.Flags().StringSliceVar(&tuiReport.Filter.Genres, "genre", nil, "only count these genres, by key or name (e.g. animation, アニメーション)")
+	tuiCmd.Flags().StringVar(&tuiReport.ExcludeTitlesFile, "exclude-titles", "", "file of work titles to leave out, one per line")
+
+	_ = tuiCmd.MarkFlagRequired("in")
+}
@@ -27,22 +27,55 @@ type Summary struct {
 }
 
 type Built struct {
-	Source      string      `json:"source,omitempty"`
-	GeneratedAt string      `json:"generated_at"`
-	Items       []BuiltItem `json:"items"`
+	SchemaVersion int         `json:"schema_version"` // see SchemaVersion
+	Source        string      `json:"source,omitempty"`
+	GeneratedAt   string      `json:"generated_at"`
+	Items         []BuiltItem `json:"items"`
 }
 
 type BuiltItem struct {
-	Date       string                `json:"date"`
-	Normalized model.NormalizedTitle `json:"normalized"`
-	Metadata   *model.Metadata       `json:"metadata,omitempty"`
+	Date        string                `json:"date"`
+	StartTime   string                `json:"start_time,omitempty"`   // RFC3339, account export only
+	DurationSec int                   `json:"duration_sec,omitempty"` // actual playback time incl. resumed parts, account export only
+	Device      string                `json:"device,omitempty"`       // device type, account export only
+	Normalized  model.NormalizedTitle `json:"normalized"`
+	Metadata    *model.Metadata       `json:"metadata,omitempty"`
+}
+
+// Minutes is the watch time of the view: the actual playback time from the
+// account export if recorded, else the runtime from the metadata. So for
+// the account export every total is time actually watched, and a title that
+// was only started counts for little.
+func (it BuiltItem) Minutes() int {
+	if it.DurationSec > 0 {
+		return (it.DurationSec + 30) / 60
+	}
+	if it.Metadata != nil {
+		return it.Metadata.Runtime
+	}
+	return 0
+}
+
+func newBuiltItem(r model.ViewingRecord, n model.NormalizedTitle, md *model.Metadata) BuiltItem {
+	it := BuiltItem{
+		Date:        r.Date.Format("2006-01-02"),
+		DurationSec: int(r.Duration.Seconds()),
+		Device:      r.Device,
+		Normalized:  n,
+		Metadata:    md,
+	}
+	if !r.StartTime.IsZero() {
+		it.StartTime = r.StartTime.Format(time.RFC3339)
+	}
+	return it
 }
 
 func Run(records []model.ViewingRecord, cache store.Cache, p provider.Provider, opts Options) (Built, Summary, error) {
 	sum := Summary{}
 	out := Built{
-		GeneratedAt: time.Now().Format(time.RFC3339),
-		Items:       make([]BuiltItem, len(records)),
+		SchemaVersion: SchemaVersion,
+		GeneratedAt:   time.Now().Format(time.RFC3339),
+		Items:         make([]BuiltItem, len(records)),
 	}
 
 	var mu sync.Mutex
@@ -69,11 +102,7 @@ func Run(records []model.ViewingRecord, cache store.Cache, p provider.Provider,
 				sum.CacheHits++
 				mu.Unlock()
 
-				out.Items[i] = BuiltItem{
-					Date:       r.Date.Format("2006-01-02"),
-					Normalized: n,
-					Metadata:   &md,
-				}
+				out.Items[i] = newBuiltItem(r, n, &md)
 				return nil
 			}
 
@@ -104,11 +133,7 @@ func Run(records []model.ViewingRecord, cache store.Cache, p provider.Provider,
 						return putErr
 					}
 
-					out.Items[i] = BuiltItem{
-						Date:       r.Date.Format("2006-01-02"),
-						Normalized: n,
-						Metadata:   &got,
-					}
+					out.Items[i] = newBuiltItem(r, n, &got)
 					return nil
 				}
 			}
@@ -117,11 +142,7 @@ func Run(records []model.ViewingRecord, cache store.Cache, p provider.Provider,
 			sum.Unresolved++
 			mu.Unlock()
 
-			out.Items[i] = BuiltItem{
-				Date:       r.Date.Format("2006-01-02"),
-				Normalized: n,
-				Metadata:   nil,
-			}
+			out.Items[i] = newBuiltItem(r, n, nil)
 			return nil
 		})
 	}
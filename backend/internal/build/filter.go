@@ -0,0 +1,192 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kmdkuk/nfrecap/internal/genre"
+)
+
+// Metadata status values of Filter.Status.
+const (
+	StatusResolved   = "resolved"
+	StatusUnresolved = "unresolved"
+)
+
+// Filter selects views of a Built. The zero value matches every view; each
+// set field narrows the selection, and list fields match any of their values.
+type Filter struct {
+	From time.Time // first day, inclusive; zero for no lower bound
+	To   time.Time // last day, inclusive; zero for no upper bound
+
+	Types     []string       // "movie", "tv" or "unknown"
+	Genres    []string       // genre keys or names in either language, e.g. "animation" or "アニメーション"
+	Title     string         // case-insensitive substring of the work title or the provider's title
+	TitleExpr *regexp.Regexp // the same, as a regular expression
+	Providers []string       // metadata provider, e.g. "tmdb"
+	Status    string         // StatusResolved or StatusUnresolved; empty for both
+
+	// ExcludeTitles leaves out works whose work title or provider's title
+	// is one of these, ignoring case; see ReadTitleList.
+	ExcludeTitles []string
+}
+
+// Validate checks the types and status.
+func (f Filter) Validate() error {
+	for _, t := range f.Types {
+		if t != "movie" && t != "tv" && t != "unknown" {
+			return fmt.Errorf("invalid type %q: must be movie, tv or unknown", t)
+		}
+	}
+	if f.Status != "" && f.Status != StatusResolved && f.Status != StatusUnresolved {
+		return fmt.Errorf("invalid status %q: must be %q or %q", f.Status, StatusResolved, StatusUnresolved)
+	}
+	if !f.From.IsZero() && !f.To.IsZero() && f.To.Before(f.From) {
+		return fmt.Errorf("invalid date range: %s is before %s", f.To.Format(dateLayout), f.From.Format(dateLayout))
+	}
+	return nil
+}
+
+// CheckGenres checks that every name of f.Genres is a canonical genre, or a
+// genre outside the taxonomy that b has, so that a misspelled genre is an
+// error rather than an empty selection.
+func (f Filter) CheckGenres(b Built) error {
+	var unknown []string
+	for _, name := range f.Genres {
+		if !genre.Known(name) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	keys := make(map[string]bool)
+	for _, it := range b.Items {
+		if it.Metadata != nil {
+			for _, k := range genre.Keys(*it.Metadata) {
+				keys[k] = true
+			}
+		}
+	}
+	for _, name := range unknown {
+		if !slices.ContainsFunc(genre.FromName(name), func(k string) bool { return keys[k] }) {
+			return fmt.Errorf("unknown genre %q: not a genre key or name, nor a genre in the data", name)
+		}
+	}
+	return nil
+}
+
+// IsZero reports whether f matches every view.
+func (f Filter) IsZero() bool {
+	return f.From.IsZero() && f.To.IsZero() && len(f.Types) == 0 && len(f.Genres) == 0 &&
+		f.Title == "" && f.TitleExpr == nil && len(f.Providers) == 0 && f.Status == "" &&
+		len(f.ExcludeTitles) == 0
+}
+
+const dateLayout = "2006-01-02"
+
+// Match reports whether it passes f. Views with an invalid date only pass
+// a filter without a date range.
+func (f Filter) Match(it BuiltItem) bool {
+	if !f.From.IsZero() || !f.To.IsZero() {
+		d, err := time.Parse(dateLayout, it.Date)
+		if err != nil {
+			return false
+		}
+		if (!f.From.IsZero() && d.Before(f.From)) || (!f.To.IsZero() && d.After(f.To)) {
+			return false
+		}
+	}
+	if len(f.Types) > 0 && !slices.Contains(f.Types, it.Normalized.Type) {
+		return false
+	}
+	switch f.Status {
+	case StatusResolved:
+		if it.Metadata == nil {
+			return false
+		}
+	case StatusUnresolved:
+		if it.Metadata != nil {
+			return false
+		}
+	}
+	if len(f.Providers) > 0 && (it.Metadata == nil || !containsFold(f.Providers, it.Metadata.Provider)) {
+		return false
+	}
+	if len(f.Genres) > 0 && !f.matchGenre(it) {
+		return false
+	}
+	titles := []string{it.Normalized.WorkTitle}
+	if it.Metadata != nil && it.Metadata.Title != "" {
+		titles = append(titles, it.Metadata.Title)
+	}
+	if len(f.ExcludeTitles) > 0 && slices.ContainsFunc(titles, func(t string) bool { return containsFold(f.ExcludeTitles, t) }) {
+		return false
+	}
+	if f.Title != "" || f.TitleExpr != nil {
+		if f.Title != "" && !slices.ContainsFunc(titles, func(t string) bool {
+			return strings.Contains(strings.ToLower(t), strings.ToLower(f.Title))
+		}) {
+			return false
+		}
+		if f.TitleExpr != nil && !slices.ContainsFunc(titles, f.TitleExpr.MatchString) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f Filter) matchGenre(it BuiltItem) bool {
+	if it.Metadata == nil {
+		return false
+	}
+	keys := genre.Keys(*it.Metadata)
+	for _, name := range f.Genres {
+		for _, k := range genre.FromName(name) {
+			if slices.Contains(keys, k) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	return slices.ContainsFunc(list, func(v string) bool { return strings.EqualFold(v, s) })
+}
+
+// ReadTitleList reads a list of titles for Filter.ExcludeTitles: one title
+// per line, skipping blank lines and lines starting with "#".
+func ReadTitleList(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read title list: %w", err)
+	}
+	var titles []string
+	for _, line := range strings.Split(strings.TrimPrefix(string(data), "\ufeff"), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			titles = append(titles, line)
+		}
+	}
+	return titles, nil
+}
+
+// Apply returns a copy of b with only the views that pass f.
+func (f Filter) Apply(b Built) Built {
+	if f.IsZero() {
+		return b
+	}
+	items := make([]BuiltItem, 0, len(b.Items))
+	for _, it := range b.Items {
+		if f.Match(it) {
+			items = append(items, it)
+		}
+	}
+	b.Items = items
+	return b
+}
@@ -0,0 +1,113 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kmdkuk/nfrecap/internal/model"
+)
+
+func filterTestBuilt() Built {
+	horror := &model.Metadata{Provider: "tmdb", ID: "movie:1", Title: "The Haunting", Genres: []string{"Horror"}, Runtime: 100}
+	anime := &model.Metadata{Provider: "TMDB", ID: "tv:2", Title: "Frieren", Genres: []string{"Animation", "Drama"}, Runtime: 25}
+	return Built{GeneratedAt: "2025-12-31T00:00:00Z", Items: []BuiltItem{
+		{Date: "2025-10-01", Normalized: model.NormalizedTitle{WorkTitle: "ホーンティング", Type: "movie"}, Metadata: horror},
+		{Date: "2025-10-31", Normalized: model.NormalizedTitle{WorkTitle: "葬送のフリーレン", Type: "tv", Season: "シーズン1"}, Metadata: anime},
+		{Date: "2025-11-01", Normalized: model.NormalizedTitle{WorkTitle: "葬送のフリーレン", Type: "tv", Season: "シーズン1"}, Metadata: anime},
+		{Date: "2025-09-30", Normalized: model.NormalizedTitle{WorkTitle: "Unknown Film", Type: "movie"}},
+		{Date: "bad", Normalized: model.NormalizedTitle{WorkTitle: "Broken", Type: "unknown"}},
+	}}
+}
+
+func titles(b Built) []string {
+	var out []string
+	for _, it := range b.Items {
+		out = append(out, it.Date+" "+it.Normalized.WorkTitle)
+	}
+	return out
+}
+
+func TestFilterApply(t *testing.T) {
+	day := func(s string) time.Time {
+		d, err := time.Parse(dateLayout, s)
+		require.NoError(t, err)
+		return d
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"zero matches all", Filter{}, []string{"2025-10-01 ホーンティング", "2025-10-31 葬送のフリーレン", "2025-11-01 葬送のフリーレン", "2025-09-30 Unknown Film", "bad Broken"}},
+		{"date range is inclusive", Filter{From: day("2025-10-01"), To: day("2025-10-31")}, []string{"2025-10-01 ホーンティング", "2025-10-31 葬送のフリーレン"}},
+		{"open upper bound", Filter{From: day("2025-10-31")}, []string{"2025-10-31 葬送のフリーレン", "2025-11-01 葬送のフリーレン"}},
+		{"type", Filter{Types: []string{"movie"}}, []string{"2025-10-01 ホーンティング", "2025-09-30 Unknown Film"}},
+		{"genre by key", Filter{Genres: []string{"horror"}}, []string{"2025-10-01 ホーンティング"}},
+		{"genre by Japanese name", Filter{Genres: []string{"アニメーション", "ホラー"}}, []string{"2025-10-01 ホーンティング", "2025-10-31 葬送のフリーレン", "2025-11-01 葬送のフリーレン"}},
+		{"title substring", Filter{Title: "フリーレン"}, []string{"2025-10-31 葬送のフリーレン", "2025-11-01 葬送のフリーレン"}},
+		{"title matches provider title", Filter{Title: "haunting"}, []string{"2025-10-01 ホーンティング"}},
+		{"title regex", Filter{TitleExpr: regexp.MustCompile(`^(Unknown|Broken)`)}, []string{"2025-09-30 Unknown Film", "bad Broken"}},
+		{"provider ignores case", Filter{Providers: []string{"tmdb"}, Types: []string{"tv"}}, []string{"2025-10-31 葬送のフリーレン", "2025-11-01 葬送のフリーレン"}},
+		{"unresolved", Filter{Status: StatusUnresolved}, []string{"2025-09-30 Unknown Film", "bad Broken"}},
+		{"resolved", Filter{Status: StatusResolved, Types: []string{"movie"}}, []string{"2025-10-01 ホーンティング"}},
+		{"exclude titles", Filter{ExcludeTitles: []string{"frieren", "Broken"}}, []string{"2025-10-01 ホーンティング", "2025-09-30 Unknown Film"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := filterTestBuilt()
+			got := tt.filter.Apply(b)
+			assert.Equal(t, tt.want, titles(got))
+			assert.Equal(t, b.GeneratedAt, got.GeneratedAt)
+			assert.Len(t, b.Items, 5, "input is not modified")
+		})
+	}
+}
+
+func TestFilterValidate(t *testing.T) {
+	assert.NoError(t, Filter{}.Validate())
+	assert.NoError(t, Filter{Types: []string{"movie", "tv", "unknown"}, Status: StatusResolved}.Validate())
+	assert.ErrorContains(t, Filter{Types: []string{"series"}}.Validate(), `invalid type "series"`)
+	assert.ErrorContains(t, Filter{Status: "pending"}.Validate(), `invalid status "pending"`)
+	to := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.ErrorContains(t, Filter{From: to.AddDate(0, 0, 1), To: to}.Validate(), "invalid date range")
+}
+
+func TestFilterCheckGenres(t *testing.T) {
+	b := Built{Items: []BuiltItem{
+		{Date: "2025-01-01", Metadata: &model.Metadata{Provider: "tmdb", Genres: []string{"Anime", "Drama"}}},
+		{Date: "2025-01-02"},
+	}}
+	assert.NoError(t, Filter{}.CheckGenres(b))
+	assert.NoError(t, Filter{Genres: []string{"animation", "ホラー", "Anime"}}.CheckGenres(b))
+	assert.ErrorContains(t, Filter{Genres: []string{"drama", "Animaton"}}.CheckGenres(b), `unknown genre "Animaton"`)
+	assert.ErrorContains(t, Filter{Genres: []string{"Anime"}}.CheckGenres(Built{}), `unknown genre "Anime"`)
+}
+
+func TestReadTitleList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kids.txt")
+	require.NoError(t, os.WriteFile(path, []byte("\ufeff# kids' shows\nBluey\r\n\n  Peppa Pig  \n"), 0644))
+	titles, err := ReadTitleList(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bluey", "Peppa Pig"}, titles)
+
+	_, err = ReadTitleList(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.ErrorContains(t, err, "failed to read title list")
+}
+
+func TestReadJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "built.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"generated_at":"2025-12-31T00:00:00Z","items":[{"date":"2025-01-01","normalized":{"raw_title":"A","work_title":"A","type":"movie"}}]}`), 0644))
+	b, err := ReadJSON(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2025-01-01 A"}, titles(b))
+
+	_, err = ReadJSON(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,32 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ReadJSON reads a file written by `nfrecap build`, migrating older schema
+// versions in memory; see Decode.
+func ReadJSON(path string) (Built, error) {
+	b, _, err := ReadJSONVersion(path)
+	return b, err
+}
+
+// ReadJSONVersion is ReadJSON that also returns the schema version the file
+// was written in.
+func ReadJSONVersion(path string) (Built, int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Built{}, 0, err
+	}
+	b, version, err := Decode(data)
+	if err != nil {
+		sep := " " // lists of problems start on a new line
+		if strings.Contains(err.Error(), "\n") {
+			sep = "\n"
+		}
+		return Built{}, version, fmt.Errorf("invalid built json %s:%s%w", path, sep, err)
+	}
+	return b, version, nil
+}
@@ -0,0 +1,214 @@
+package build
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kmdkuk/nfrecap/internal/title"
+)
+
+// SchemaVersion is the version of the built JSON written by Run. It is
+// bumped on any change that older files do not satisfy, together with a
+// migration and built.schema.json. Files from before versioning are
+// version 0.
+const SchemaVersion = 1
+
+// JSONSchema is the JSON Schema of the current SchemaVersion.
+//
+//go:embed built.schema.json
+var JSONSchema []byte
+
+// migrations[v] upgrades a decoded document of version v to v+1. They work
+// on the raw document, so that they can handle fields Built no longer has.
+var migrations = []func(doc map[string]any) error{
+	migrateV0,
+}
+
+// migrateV0 adds the season and episode numbers, which builds before
+// versioning did not record.
+func migrateV0(doc map[string]any) error {
+	items, _ := doc["items"].([]any)
+	for _, v := range items {
+		it, _ := v.(map[string]any)
+		n, _ := it["normalized"].(map[string]any)
+		if n == nil {
+			continue
+		}
+		if s, ok := n["season"].(string); ok && n["season_number"] == nil {
+			if num := title.SeasonNumber(s); num > 0 {
+				n["season_number"] = num
+			}
+		}
+		if s, ok := n["episode_title"].(string); ok && n["episode_number"] == nil {
+			if num := title.EpisodeNumber(s); num > 0 {
+				n["episode_number"] = num
+			}
+		}
+	}
+	return nil
+}
+
+// Decode parses a built JSON document. Older schema versions are migrated
+// to SchemaVersion; the document must then match the schema exactly, so
+// that misspelled fields or files of other tools are reported rather than
+// read as empty values. It also returns the version data was written in.
+func Decode(data []byte) (Built, int, error) {
+	var doc map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return Built{}, 0, jsonError(data, err)
+	}
+	if doc == nil {
+		return Built{}, 0, errors.New("not a JSON object")
+	}
+
+	version := 0
+	if v, ok := doc["schema_version"]; ok {
+		n, ok := v.(json.Number)
+		i, err := n.Int64()
+		if !ok || err != nil || i < 1 {
+			return Built{}, 0, fmt.Errorf("schema_version: %v is not a version number", v)
+		}
+		version = int(i)
+	}
+	if version > SchemaVersion {
+		return Built{}, version, fmt.Errorf("schema version %d is newer than this nfrecap supports (%d); upgrade nfrecap", version, SchemaVersion)
+	}
+	if version < SchemaVersion {
+		for v := version; v < SchemaVersion; v++ {
+			if err := migrations[v](doc); err != nil {
+				return Built{}, version, fmt.Errorf("migrating from schema version %d: %w", v, err)
+			}
+		}
+		doc["schema_version"] = SchemaVersion
+		var err error
+		if data, err = json.Marshal(doc); err != nil {
+			return Built{}, version, err
+		}
+	}
+
+	var b Built
+	dec = json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&b); err != nil {
+		return Built{}, version, jsonError(data, err)
+	}
+	if err := b.Validate(); err != nil {
+		return Built{}, version, err
+	}
+	return b, version, nil
+}
+
+// jsonError adds the position of syntax errors and the field of type
+// errors, and drops the "json: " prefix.
+func jsonError(data []byte, err error) error {
+	var syntax *json.SyntaxError
+	var typ *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntax):
+		line, col := position(data, syntax.Offset)
+		return fmt.Errorf("line %d, column %d: %s", line, col, syntax)
+	case errors.As(err, &typ) && typ.Field != "":
+		path := indexRe.ReplaceAllString(typ.Field, "[$1]") // "items.2.date" -> "items[2].date", as in Validate
+		return fmt.Errorf("%s: cannot use JSON %s as %s", path, typ.Value, typ.Type)
+	}
+	return errors.New(strings.TrimPrefix(err.Error(), "json: "))
+}
+
+var indexRe = regexp.MustCompile(`\.(\d+)`)
+
+// position converts a byte offset of data into a 1-based line and column.
+func position(data []byte, offset int64) (int, int) {
+	before := data[:min(int(offset), len(data))]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := len(before) - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
+
+// maxErrors is how many problems Validate lists before summing up the rest.
+const maxErrors = 10
+
+// Validate checks what the JSON Schema requires beyond the field types:
+// required fields, dates and enumerations. Every problem is reported with
+// its path, e.g. "items[3].date".
+func (b Built) Validate() error {
+	var errs []string
+	add := func(path, format string, args ...any) {
+		errs = append(errs, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if b.SchemaVersion != SchemaVersion {
+		add("schema_version", "got %d, want %d", b.SchemaVersion, SchemaVersion)
+	}
+	if _, err := time.Parse(time.RFC3339, b.GeneratedAt); err != nil {
+		add("generated_at", "%q is not an RFC 3339 time", b.GeneratedAt)
+	}
+	if b.Items == nil {
+		add("items", "missing")
+	}
+	for i, it := range b.Items {
+		p := fmt.Sprintf("items[%d]", i)
+		if _, err := time.Parse(dateLayout, it.Date); err != nil {
+			add(p+".date", "%q is not a YYYY-MM-DD date", it.Date)
+		}
+		if it.StartTime != "" {
+			if _, err := time.Parse(time.RFC3339, it.StartTime); err != nil {
+				add(p+".start_time", "%q is not an RFC 3339 time", it.StartTime)
+			}
+		}
+		if it.DurationSec < 0 {
+			add(p+".duration_sec", "negative")
+		}
+
+		n := it.Normalized
+		if n.RawTitle == "" {
+			add(p+".normalized.raw_title", "missing")
+		}
+		if n.WorkTitle == "" {
+			add(p+".normalized.work_title", "missing")
+		}
+		if n.Type != "movie" && n.Type != "tv" && n.Type != "unknown" {
+			add(p+".normalized.type", "%q is not movie, tv or unknown", n.Type)
+		}
+		if n.SeasonNumber < 0 || n.EpisodeNumber < 0 {
+			add(p+".normalized", "negative season or episode number")
+		}
+
+		md := it.Metadata
+		if md == nil {
+			continue
+		}
+		if md.Provider == "" {
+			add(p+".metadata.provider", "missing")
+		}
+		if md.ID == "" {
+			add(p+".metadata.id", "missing")
+		}
+		if len(md.GenreIDs) > 0 && len(md.GenreIDs) != len(md.Genres) {
+			add(p+".metadata.genre_ids", "%d IDs for %d genres", len(md.GenreIDs), len(md.Genres))
+		}
+		if md.Year < 0 || md.Runtime < 0 {
+			add(p+".metadata", "negative year or runtime")
+		}
+		for j, s := range md.Seasons {
+			if s.Number < 0 || s.EpisodeCount < 0 {
+				add(fmt.Sprintf("%s.metadata.seasons[%d]", p, j), "negative number or episode count")
+			}
+		}
+	}
+
+	switch {
+	case len(errs) == 0:
+		return nil
+	case len(errs) > maxErrors:
+		errs = append(errs[:maxErrors], fmt.Sprintf("... and %d more", len(errs)-maxErrors))
+	}
+	return errors.New(strings.Join(errs, "\n"))
+}
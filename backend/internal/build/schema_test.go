@@ -0,0 +1,173 @@
+package build
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kmdkuk/nfrecap/internal/csvio"
+	"github.com/kmdkuk/nfrecap/internal/model"
+)
+
+func TestDecodeMigratesV0(t *testing.T) {
+	v0 := `{
+  "generated_at": "2024-12-31T09:00:00+09:00",
+  "items": [
+    {"date": "2024-01-01", "normalized": {"raw_title": "Show: シーズン2: 第3話", "work_title": "Show", "type": "tv", "season": "シーズン2", "episode_title": "第3話"}},
+    {"date": "2024-01-02", "normalized": {"raw_title": "Film", "work_title": "Film", "type": "movie"}, "metadata": {"provider": "tmdb", "id": "movie:1", "title": "Film"}}
+  ]
+}`
+	b, version, err := Decode([]byte(v0))
+	require.NoError(t, err)
+	assert.Equal(t, 0, version)
+	assert.Equal(t, SchemaVersion, b.SchemaVersion)
+	require.Len(t, b.Items, 2)
+	assert.Equal(t, 2, b.Items[0].Normalized.SeasonNumber)
+	assert.Equal(t, 3, b.Items[0].Normalized.EpisodeNumber)
+	assert.Zero(t, b.Items[1].Normalized.SeasonNumber)
+	assert.Equal(t, "movie:1", b.Items[1].Metadata.ID)
+}
+
+func TestDecodeCurrent(t *testing.T) {
+	want := Built{SchemaVersion: SchemaVersion, Source: "history.csv", GeneratedAt: "2024-12-31T09:00:00+09:00", Items: []BuiltItem{
+		{Date: "2024-01-01", StartTime: "2024-01-01T21:00:00+09:00", DurationSec: 1500, Device: "Apple TV",
+			Normalized: model.NormalizedTitle{RawTitle: "Show: シーズン1: 第1話", WorkTitle: "Show", Type: "tv", Season: "シーズン1", EpisodeTitle: "第1話", SeasonNumber: 1, EpisodeNumber: 1},
+			Metadata:   &model.Metadata{Provider: "tmdb", ID: "tv:1", Title: "Show", Genres: []string{"Drama"}, GenreIDs: []int{18}, Seasons: []model.Season{{Number: 1, EpisodeCount: 8}}}},
+	}}
+	data, err := json.Marshal(want)
+	require.NoError(t, err)
+
+	got, version, err := Decode(data)
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, version)
+	assert.Equal(t, want, got)
+}
+
+// TestRunReadBack checks that whatever build writes for odd CSV rows can be
+// read again.
+func TestRunReadBack(t *testing.T) {
+	for name, csv := range map[string]string{
+		"simple":         "Title,Date\n,1/1/25\n: Ep,1/2/25\nShow: シーズン1: 第1話,1/3/25\nFilm,1/4/25\n",
+		"account export": "Profile Name,Start Time,Duration,Title\na,2025-01-01 10:00:00,00:10:00,\na,2025-01-02 10:00:00,,: Ep\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			records, err := csvio.ParseNetflixCSV(strings.NewReader(csv))
+			require.NoError(t, err)
+
+			cache := new(MockCache)
+			cache.On("Get", "Film", "movie").Return(model.Metadata{Provider: "tmdb", ID: "movie:1", Title: "Film", Runtime: 100}, true, nil)
+			cache.On("Get", mock.Anything, mock.Anything).Return(model.Metadata{}, false, nil)
+			built, _, err := Run(records, cache, new(MockProvider), Options{})
+			require.NoError(t, err)
+
+			data, err := json.Marshal(built)
+			require.NoError(t, err)
+			path := filepath.Join(t.TempDir(), "built.json")
+			require.NoError(t, os.WriteFile(path, data, 0644))
+			got, err := ReadJSON(path)
+			require.NoError(t, err)
+			assert.Equal(t, built, got)
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	item := `{"date": "2024-01-01", "normalized": {"raw_title": "A", "work_title": "A", "type": "movie"}}`
+	doc := func(fields string) string {
+		return fmt.Sprintf(`{"schema_version": 1, "generated_at": "2024-12-31T09:00:00Z", %s}`, fields)
+	}
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"newer version", `{"schema_version": 99, "items": []}`, "schema version 99 is newer than this nfrecap supports (1); upgrade nfrecap"},
+		{"bad version", `{"schema_version": "1"}`, "schema_version: 1 is not a version number"},
+		{"not an object", `null`, "not a JSON object"},
+		{"syntax error", "{\n  \"items\": [\n    {\"date\": \"2024-01-01\",}\n  ]\n}", "line 3, column 28: invalid character '}' looking for beginning of object key string"},
+		{"type error", doc(`"items": [{"date": "2024-01-01", "duration_sec": "60"}]`), "items[0].duration_sec: cannot use JSON string as int"},
+		{"unknown field", doc(`"items": [], "itmes": []`), `unknown field "itmes"`},
+		{"missing items", `{"schema_version": 1, "generated_at": "2024-12-31T09:00:00Z"}`, "items: missing"},
+		{"invalid values", doc(`"items": [` + item + `,
+			{"date": "2024/01/02", "start_time": "21:00", "normalized": {"raw_title": "B", "work_title": "", "type": "film"}},
+			{"date": "2024-01-03", "normalized": {"raw_title": "C", "work_title": "C", "type": "tv"}, "metadata": {"provider": "tmdb", "id": "", "title": "C", "genres": ["Drama"], "genre_ids": [18, 35]}}]`),
+			strings.Join([]string{
+				`items[1].date: "2024/01/02" is not a YYYY-MM-DD date`,
+				`items[1].start_time: "21:00" is not an RFC 3339 time`,
+				`items[1].normalized.work_title: missing`,
+				`items[1].normalized.type: "film" is not movie, tv or unknown`,
+				`items[2].metadata.id: missing`,
+				`items[2].metadata.genre_ids: 2 IDs for 1 genres`,
+			}, "\n")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Decode([]byte(tt.data))
+			require.Error(t, err)
+			assert.Equal(t, tt.want, err.Error())
+		})
+	}
+
+	// long lists are cut
+	_, _, err := Decode([]byte(`{"schema_version": 1, "generated_at": "yesterday", "items": [` +
+		strings.Repeat(`{"date": "x", "normalized": {"raw_title": "A", "work_title": "A", "type": "movie"}},`, 11) + item + `]}`))
+	require.Error(t, err)
+	lines := strings.Split(err.Error(), "\n")
+	require.Len(t, lines, maxErrors+1)
+	assert.Equal(t, `generated_at: "yesterday" is not an RFC 3339 time`, lines[0])
+	assert.Equal(t, "... and 2 more", lines[maxErrors])
+}
+
+// TestJSONSchema checks that built.schema.json describes every field of
+// Built, and only those.
+func TestJSONSchema(t *testing.T) {
+	var schema struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+		Defs       map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(JSONSchema, &schema))
+	assert.Contains(t, string(schema.Properties["schema_version"]), fmt.Sprintf(`"const": %d`, SchemaVersion))
+
+	var seasons struct {
+		Items struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(schema.Defs["metadata"].Properties["seasons"], &seasons))
+
+	for _, tt := range []struct {
+		typ        reflect.Type
+		properties map[string]json.RawMessage
+	}{
+		{reflect.TypeFor[Built](), schema.Properties},
+		{reflect.TypeFor[BuiltItem](), schema.Defs["item"].Properties},
+		{reflect.TypeFor[model.NormalizedTitle](), schema.Defs["normalized"].Properties},
+		{reflect.TypeFor[model.Metadata](), schema.Defs["metadata"].Properties},
+		{reflect.TypeFor[model.Season](), seasons.Items.Properties},
+	} {
+		var fields []string
+		for i := range tt.typ.NumField() {
+			name, _, _ := strings.Cut(tt.typ.Field(i).Tag.Get("json"), ",")
+			fields = append(fields, name)
+		}
+		var props []string
+		for name := range tt.properties {
+			props = append(props, name)
+		}
+		slices.Sort(fields)
+		slices.Sort(props)
+		assert.Equal(t, fields, props, tt.typ.Name())
+	}
+}
@@ -5,23 +5,45 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/kmdkuk/nfrecap/internal/model"
 )
 
+type Options struct {
+	// Location is used to convert UTC start times of the account export
+	// into local dates and hours. Defaults to time.Local.
+	Location *time.Location
+}
+
 func ReadNetflixCSV(path string) ([]model.ViewingRecord, error) {
+	return ReadNetflixCSVWithOptions(path, Options{})
+}
+
+func ReadNetflixCSVWithOptions(path string, opts Options) ([]model.ViewingRecord, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
 
-	return ParseNetflixCSV(f)
+	return ParseNetflixCSVWithOptions(f, opts)
 }
 
 func ParseNetflixCSV(r io.Reader) ([]model.ViewingRecord, error) {
+	return ParseNetflixCSVWithOptions(r, Options{})
+}
+
+// ParseNetflixCSVWithOptions accepts both the viewing history download
+// (Title,Date) and the account export ViewingActivity.csv, which has
+// "Start Time" and "Duration" columns.
+func ParseNetflixCSVWithOptions(r io.Reader, opts Options) ([]model.ViewingRecord, error) {
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+
 	cr := csv.NewReader(r)
 	cr.FieldsPerRecord = -1
 
@@ -33,6 +55,10 @@ func ParseNetflixCSV(r io.Reader) ([]model.ViewingRecord, error) {
 		return nil, fmt.Errorf("empty csv")
 	}
 
+	if cols := columnIndex(rows[0]); cols.has("Start Time", "Title") {
+		return parseViewingActivity(rows, cols, opts.Location)
+	}
+
 	out := make([]model.ViewingRecord, 0, len(rows)-1)
 	for i, row := range rows {
 		if i == 0 {
@@ -43,6 +69,9 @@ func ParseNetflixCSV(r io.Reader) ([]model.ViewingRecord, error) {
 		}
 		title := strings.TrimSpace(row[0])
 		ds := strings.TrimSpace(row[1])
+		if title == "" {
+			continue // like the account export, rows without a title are no views
+		}
 
 		// Netflix viewing history often uses M/D/YY like "12/13/25"
 		d, err := time.Parse("1/2/06", ds)
@@ -57,3 +86,133 @@ func ParseNetflixCSV(r io.Reader) ([]model.ViewingRecord, error) {
 	}
 	return out, nil
 }
+
+// resumeGap is the longest pause after which playing the same title again
+// still continues the same view.
+const resumeGap = 30 * time.Minute
+
+// parseViewingActivity reads ViewingActivity.csv from the account data export.
+// Start times are UTC ("2025-06-07 12:34:56"), durations are "HH:MM:SS".
+// Trailers and other supplemental videos are skipped. The export has a row
+// per playback, so a title that is paused and resumed within resumeGap is
+// merged into one record with the summed duration.
+func parseViewingActivity(rows [][]string, cols columns, loc *time.Location) ([]model.ViewingRecord, error) {
+	out := make([]model.ViewingRecord, 0, len(rows)-1)
+	var profiles []string
+	for i, row := range rows {
+		if i == 0 {
+			continue // header
+		}
+		if cols.get(row, "Supplemental Video Type") != "" {
+			continue
+		}
+		title := cols.get(row, "Title")
+		ss := cols.get(row, "Start Time")
+		if title == "" || ss == "" {
+			continue
+		}
+
+		st, err := time.ParseInLocation("2006-01-02 15:04:05", ss, time.UTC)
+		if err != nil {
+			return nil, fmt.Errorf("start time parse failed at line %d: %q: %w", i+1, ss, err)
+		}
+		st = st.In(loc)
+
+		var dur time.Duration
+		if ds := cols.get(row, "Duration"); ds != "" {
+			dur, err = parseClockDuration(ds)
+			if err != nil {
+				return nil, fmt.Errorf("duration parse failed at line %d: %q: %w", i+1, ds, err)
+			}
+		}
+
+		out = append(out, model.ViewingRecord{
+			Title:     title,
+			Date:      time.Date(st.Year(), st.Month(), st.Day(), 0, 0, 0, 0, time.Local),
+			StartTime: st,
+			Duration:  dur,
+			Device:    cols.get(row, "Device Type"),
+		})
+		profiles = append(profiles, cols.get(row, "Profile Name"))
+	}
+	return mergeResumed(out, profiles), nil
+}
+
+// mergeResumed adds the duration of every record that resumes the previous
+// playback of the same title by the same profile to that playback, and drops
+// it. The order of the remaining records is kept.
+func mergeResumed(recs []model.ViewingRecord, profiles []string) []model.ViewingRecord {
+	order := make([]int, len(recs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return recs[order[a]].StartTime.Before(recs[order[b]].StartTime)
+	})
+
+	type playback struct {
+		first int       // index of the record that is kept
+		end   time.Time // end of the latest part
+	}
+	last := make(map[string]playback) // profile and title -> playback being continued
+	merged := make([]bool, len(recs))
+	for _, i := range order {
+		k := profiles[i] + "\x00" + recs[i].Title
+		end := recs[i].StartTime.Add(recs[i].Duration)
+		if p, ok := last[k]; ok && !recs[i].StartTime.After(p.end.Add(resumeGap)) {
+			recs[p.first].Duration += recs[i].Duration
+			merged[i] = true
+			if p.end.After(end) {
+				end = p.end
+			}
+			last[k] = playback{first: p.first, end: end}
+			continue
+		}
+		last[k] = playback{first: i, end: end}
+	}
+
+	out := recs[:0]
+	for i, r := range recs {
+		if !merged[i] {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func parseClockDuration(s string) (time.Duration, error) {
+	var h, m, sec int
+	if _, err := fmt.Sscanf(s, "%d:%d:%d", &h, &m, &sec); err != nil {
+		return 0, err
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(sec)*time.Second, nil
+}
+
+// columns maps header names to their index.
+type columns map[string]int
+
+func columnIndex(header []string) columns {
+	c := make(columns, len(header))
+	for i, h := range header {
+		// the export is written with a UTF-8 BOM
+		c[strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))] = i
+	}
+	return c
+}
+
+func (c columns) has(names ...string) bool {
+	for _, n := range names {
+		if _, ok := c[n]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (c columns) get(row []string, name string) string {
+	i, ok := c[name]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
@@ -3,6 +3,7 @@ package csvio
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -47,6 +48,17 @@ Inception`
 		assert.Len(t, recs, 0) // Should skip short row
 	})
 
+	t.Run("Empty Title", func(t *testing.T) {
+		input := `Title,Date
+,1/1/23
+"  ",1/2/23
+Inception,1/3/23`
+		recs, err := ParseNetflixCSV(strings.NewReader(input))
+		require.NoError(t, err)
+		require.Len(t, recs, 1)
+		assert.Equal(t, "Inception", recs[0].Title)
+	})
+
 	t.Run("Invalid Date", func(t *testing.T) {
 		input := `Title,Date
 Inception,2023-01-01` // Wrong format, expects M/D/YY (1/1/23)
@@ -66,3 +78,54 @@ Movie B,01/01/23`
 		assert.Equal(t, "2023-01-01", recs[1].Date.Format("2006-01-02"))
 	})
 }
+
+func TestParseNetflixCSV_ViewingActivity(t *testing.T) {
+	input := "\ufeffProfile Name,Start Time,Duration,Attributes,Title,Supplemental Video Type,Device Type,Bookmark,Latest Bookmark,Country\n" +
+		`kmdkuk,2025-06-07 15:30:00,01:31:05,,駒田蒸留所へようこそ,,Sony PS4,01:31:05,01:31:05,JP (Japan)
+kmdkuk,2025-06-07 15:28:00,00:00:40,,Komada: Trailer,TRAILER,Sony PS4,00:00:40,00:00:40,JP (Japan)
+kmdkuk,2025-06-06 23:10:00,00:45:00,,Dark: Season 1: Secrets,,Chrome PC (Cadmium),00:45:00,00:45:00,JP (Japan)`
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	recs, err := ParseNetflixCSVWithOptions(strings.NewReader(input), Options{Location: tokyo})
+	require.NoError(t, err)
+	require.Len(t, recs, 2) // trailer skipped
+
+	assert.Equal(t, "駒田蒸留所へようこそ", recs[0].Title)
+	assert.Equal(t, "2025-06-08", recs[0].Date.Format("2006-01-02")) // 00:30 JST next day
+	assert.Equal(t, "2025-06-08T00:30:00+09:00", recs[0].StartTime.Format(time.RFC3339))
+	assert.Equal(t, 91*time.Minute+5*time.Second, recs[0].Duration)
+	assert.Equal(t, "Sony PS4", recs[0].Device)
+
+	assert.Equal(t, "2025-06-07", recs[1].Date.Format("2006-01-02"))
+	assert.Equal(t, 8, recs[1].StartTime.Hour())
+
+	t.Run("Resumed Playback", func(t *testing.T) {
+		input := `Profile Name,Start Time,Duration,Title,Device Type
+kmdkuk,2025-06-07 22:00:00,00:20:00,Dark: Season 1: Secrets,TV
+a,2025-06-07 21:20:00,00:10:00,Dark: Season 1: Secrets,TV
+kmdkuk,2025-06-07 21:20:00,00:10:00,Dark: Season 1: Secrets,Phone
+kmdkuk,2025-06-07 21:00:00,00:15:00,Dark: Season 1: Secrets,Phone
+kmdkuk,2025-06-06 21:00:00,00:50:00,Dark: Season 1: Secrets,TV`
+		recs, err := ParseNetflixCSVWithOptions(strings.NewReader(input), Options{Location: time.UTC})
+		require.NoError(t, err)
+		require.Len(t, recs, 3)
+
+		// three parts with pauses of 5 and 30 minutes, kept at the first start
+		assert.Equal(t, "2025-06-07T21:00:00Z", recs[1].StartTime.Format(time.RFC3339))
+		assert.Equal(t, 45*time.Minute, recs[1].Duration)
+		assert.Equal(t, "Phone", recs[1].Device)
+		// another profile
+		assert.Equal(t, 10*time.Minute, recs[0].Duration)
+		// the day before
+		assert.Equal(t, 50*time.Minute, recs[2].Duration)
+	})
+
+	t.Run("Invalid Start Time", func(t *testing.T) {
+		input := `Profile Name,Start Time,Duration,Title
+kmdkuk,6/7/25,00:10:00,Inception`
+		_, err := ParseNetflixCSV(strings.NewReader(input))
+		assert.ErrorContains(t, err, "start time parse failed")
+	})
+}
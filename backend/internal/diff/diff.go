@@ -0,0 +1,439 @@
+// Package diff compares two builds of the same viewing history, to review
+// what new normalization rules or a fresher metadata cache change before
+// adopting them.
+package diff
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/kmdkuk/nfrecap/internal/recap"
+)
+
+// Fields of a view that Compare reports changes of.
+const (
+	FieldWorkTitle = "work_title"
+	FieldType      = "type"
+	FieldSeason    = "season"
+	FieldEpisode   = "episode"
+	FieldMatch     = "match"    // the provider's work the view resolved to
+	FieldMetadata  = "metadata" // details of the same provider's work
+)
+
+const dateLayout = "2006-01-02"
+
+// Options controls Compare.
+type Options struct {
+	// Period of the headline stats; zero means every day of both builds.
+	Period recap.Period
+}
+
+// View identifies a view of the viewing history.
+type View struct {
+	Date     string `json:"date"`
+	RawTitle string `json:"raw_title"`
+}
+
+// Change is a field that changed the same way for one or more views, e.g.
+// the work title of every episode of a series.
+type Change struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+	Views []View `json:"views"`
+}
+
+// Work is a work whose views gained or lost metadata.
+type Work struct {
+	Title    string         `json:"title"` // work title in the build that has the metadata
+	Type     string         `json:"type"`
+	Views    int            `json:"views"`
+	Metadata model.Metadata `json:"metadata"`
+}
+
+// Headline is the totals of a build over the period.
+type Headline struct {
+	Views         int `json:"views"`
+	DurationMin   int `json:"duration_min"`
+	ActiveDays    int `json:"active_days"`
+	ResolvedViews int `json:"resolved_views"`
+	Works         int `json:"works"`
+}
+
+// Result is how the new build differs from the old one.
+type Result struct {
+	OldViews int `json:"old_views"`
+	NewViews int `json:"new_views"`
+
+	Added   []build.BuiltItem `json:"added"`   // views only in the new build
+	Removed []build.BuiltItem `json:"removed"` // views only in the old build
+
+	Changes    []Change `json:"changes"`    // most views first
+	Resolved   []Work   `json:"resolved"`   // works with views that have metadata only in the new build
+	Unresolved []Work   `json:"unresolved"` // works with views that have metadata only in the old build
+
+	Period Period    `json:"period"`
+	Old    Headline  `json:"old"`
+	New    Headline  `json:"new"`
+	Stats  StatsDiff `json:"stats"` // the new stats relative to the old ones
+}
+
+// Period is the period of the headline stats.
+type Period struct {
+	From string `json:"from"` // YYYY-MM-DD
+	To   string `json:"to"`
+}
+
+// StatsDiff is how the new stats differ from the old ones over the period;
+// the totals themselves are in Result.Old and Result.New.
+type StatsDiff struct {
+	ViewsDelta        int          `json:"views_delta"`
+	DurationMinDelta  int          `json:"duration_min_delta"`
+	DurationPct       float64      `json:"duration_pct"` // change relative to the old minutes, 0 if there are none
+	ActiveDaysDelta   int          `json:"active_days_delta"`
+	GenreShifts       []GenreShift `json:"genre_shifts"`        // sorted by absolute share change
+	NewTopGenres      []string     `json:"new_top_genres"`      // in the new top genres but not in the old ones
+	CarriedOverSeries []string     `json:"carried_over_series"` // series with views in both builds
+	MonthShifts       []MonthShift `json:"month_shifts"`        // sorted by absolute duration change
+}
+
+// GenreShift is the change of a genre's share of the minutes, in percent.
+type GenreShift struct {
+	Key      string  `json:"key"`
+	Name     string  `json:"name"`
+	OldShare float64 `json:"old_share"`
+	NewShare float64 `json:"new_share"`
+	DeltaPt  float64 `json:"delta_pt"` // percentage points
+}
+
+// MonthShift is the change of the minutes in a month of the year.
+type MonthShift struct {
+	Month          string `json:"month"` // "january" … "december"
+	OldDurationMin int    `json:"old_duration_min"`
+	NewDurationMin int    `json:"new_duration_min"`
+	DeltaMin       int    `json:"delta_min"`
+}
+
+// IsZero reports whether the builds have the same views, normalized and
+// resolved the same way.
+func (r Result) IsZero() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Changes) == 0 &&
+		len(r.Resolved) == 0 && len(r.Unresolved) == 0
+}
+
+// viewKey matches the views of two builds: the viewing history does not
+// change between builds, only how it is normalized and resolved.
+func viewKey(it build.BuiltItem) string {
+	return it.Date + "\x00" + it.StartTime + "\x00" + it.Normalized.RawTitle
+}
+
+// Compare compares the views of two builds of the same viewing history.
+func Compare(oldBuilt, newBuilt build.Built, opts Options) Result {
+	r := Result{OldViews: len(oldBuilt.Items), NewViews: len(newBuilt.Items)}
+
+	// Views with the same key are paired in order.
+	pending := make(map[string][]build.BuiltItem)
+	for _, it := range oldBuilt.Items {
+		k := viewKey(it)
+		pending[k] = append(pending[k], it)
+	}
+	changes := make(map[[3]string]*Change)
+	resolved := make(map[string]*Work)
+	unresolved := make(map[string]*Work)
+	for _, it := range newBuilt.Items {
+		k := viewKey(it)
+		if len(pending[k]) == 0 {
+			r.Added = append(r.Added, it)
+			continue
+		}
+		old := pending[k][0]
+		pending[k] = pending[k][1:]
+
+		for _, c := range fieldChanges(old, it) {
+			key := [3]string{c.Field, c.Old, c.New}
+			if changes[key] == nil {
+				changes[key] = &Change{Field: c.Field, Old: c.Old, New: c.New}
+			}
+			changes[key].Views = append(changes[key].Views, View{Date: it.Date, RawTitle: it.Normalized.RawTitle})
+		}
+		switch {
+		case old.Metadata == nil && it.Metadata != nil:
+			addWork(resolved, it)
+		case old.Metadata != nil && it.Metadata == nil:
+			addWork(unresolved, old)
+		}
+	}
+	for _, it := range oldBuilt.Items {
+		k := viewKey(it)
+		if len(pending[k]) > 0 {
+			r.Removed = append(r.Removed, pending[k][0])
+			pending[k] = pending[k][1:]
+		}
+	}
+
+	for _, c := range changes {
+		r.Changes = append(r.Changes, *c)
+	}
+	sort.Slice(r.Changes, func(i, j int) bool {
+		a, b := r.Changes[i], r.Changes[j]
+		if len(a.Views) != len(b.Views) {
+			return len(a.Views) > len(b.Views)
+		}
+		if a.Field != b.Field {
+			return a.Field < b.Field
+		}
+		if a.Old != b.Old {
+			return a.Old < b.Old
+		}
+		return a.New < b.New
+	})
+	r.Resolved = sortedWorks(resolved)
+	r.Unresolved = sortedWorks(unresolved)
+
+	period := opts.Period
+	if period.From.IsZero() {
+		period = span(oldBuilt, newBuilt)
+	}
+	if !period.From.IsZero() {
+		r.Period = Period{From: period.From.Format(dateLayout), To: period.To.Format(dateLayout)}
+	}
+	statsOpts := recap.Options{Period: period, Config: recap.Config{Lang: "en"}} // genre names for WriteMarkdown
+	oldStats := recap.ComputeStatsWithOptions(oldBuilt, statsOpts)
+	newStats := recap.ComputeStatsWithOptions(newBuilt, statsOpts)
+	r.Old = headline(oldStats)
+	r.New = headline(newStats)
+	r.Stats = statsDiff(recap.Compare(newStats, oldStats))
+	return r
+}
+
+// statsDiff converts the recap comparison into the JSON names of Result.
+func statsDiff(d recap.StatsDiff) StatsDiff {
+	out := StatsDiff{
+		ViewsDelta:        d.TotalViewsDelta,
+		DurationMinDelta:  d.TotalDurationMinDelta,
+		DurationPct:       d.TotalDurationPct,
+		ActiveDaysDelta:   d.ActiveDaysDelta,
+		NewTopGenres:      d.NewTopGenres,
+		CarriedOverSeries: d.CarriedOverSeries,
+	}
+	for _, g := range d.GenreShifts {
+		out.GenreShifts = append(out.GenreShifts, GenreShift{Key: g.Key, Name: g.Name, OldShare: g.BaseShare, NewShare: g.Share, DeltaPt: g.DeltaPt})
+	}
+	for _, m := range d.MonthShifts {
+		out.MonthShifts = append(out.MonthShifts, MonthShift{
+			Month:          strings.ToLower(m.Month.String()),
+			OldDurationMin: m.BaseDurationMin,
+			NewDurationMin: m.DurationMin,
+			DeltaMin:       m.DeltaMin,
+		})
+	}
+	return out
+}
+
+// fieldChanges lists the changed fields of a view, leaving out metadata
+// that was gained or lost (see Result.Resolved and Result.Unresolved).
+func fieldChanges(old, cur build.BuiltItem) []Change {
+	var out []Change
+	add := func(field, o, n string) {
+		if o != n {
+			out = append(out, Change{Field: field, Old: o, New: n})
+		}
+	}
+	add(FieldWorkTitle, old.Normalized.WorkTitle, cur.Normalized.WorkTitle)
+	add(FieldType, old.Normalized.Type, cur.Normalized.Type)
+	add(FieldSeason, old.Normalized.Season, cur.Normalized.Season)
+	add(FieldEpisode, old.Normalized.EpisodeTitle, cur.Normalized.EpisodeTitle)
+	if old.Metadata != nil && cur.Metadata != nil {
+		o, n := *old.Metadata, *cur.Metadata
+		if o.Provider != n.Provider || o.ID != n.ID {
+			add(FieldMatch, matchLabel(o), matchLabel(n))
+		} else {
+			add(FieldMetadata, detailLabel(o), detailLabel(n))
+		}
+	}
+	return out
+}
+
+// matchLabel is e.g. "tmdb tv:1 Show (2020)".
+func matchLabel(md model.Metadata) string {
+	s := md.Provider + " " + md.ID + " " + md.Title
+	if md.Year > 0 {
+		s += " (" + strconv.Itoa(md.Year) + ")"
+	}
+	return s
+}
+
+// detailLabel sums up the metadata details that the stats use.
+func detailLabel(md model.Metadata) string {
+	parts := []string{md.Title}
+	if md.Year > 0 {
+		parts = append(parts, strconv.Itoa(md.Year))
+	}
+	if len(md.Genres) > 0 {
+		parts = append(parts, strings.Join(md.Genres, ", "))
+	}
+	if md.Runtime > 0 {
+		parts = append(parts, fmt.Sprintf("%d min", md.Runtime))
+	}
+	if n := md.TotalEpisodes(); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d episodes", n))
+	}
+	return strings.Join(parts, " / ")
+}
+
+func addWork(works map[string]*Work, it build.BuiltItem) {
+	key := it.Normalized.WorkTitle + "|" + it.Normalized.Type
+	w, ok := works[key]
+	if !ok {
+		w = &Work{Title: it.Normalized.WorkTitle, Type: it.Normalized.Type, Metadata: *it.Metadata}
+		works[key] = w
+	}
+	w.Views++
+}
+
+func sortedWorks(works map[string]*Work) []Work {
+	out := make([]Work, 0, len(works))
+	for _, w := range works {
+		out = append(out, *w)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Views != out[j].Views {
+			return out[i].Views > out[j].Views
+		}
+		return out[i].Title < out[j].Title
+	})
+	return out
+}
+
+// span is the period from the first to the last day of either build.
+func span(builds ...build.Built) recap.Period {
+	var p recap.Period
+	for _, b := range builds {
+		for _, it := range b.Items {
+			d, err := time.Parse(dateLayout, it.Date)
+			if err != nil {
+				continue
+			}
+			if p.From.IsZero() || d.Before(p.From) {
+				p.From = d
+			}
+			if d.After(p.To) {
+				p.To = d
+			}
+		}
+	}
+	return p
+}
+
+func headline(s recap.Stats) Headline {
+	return Headline{
+		Views:         s.TotalViews,
+		DurationMin:   s.TotalDurationMin,
+		ActiveDays:    s.ActiveDays,
+		ResolvedViews: s.TotalViews - s.UnresolvedCount,
+		Works:         s.Habits.Works,
+	}
+}
+
+// sampleSize is how many views of a change WriteMarkdown lists.
+const sampleSize = 3
+
+// WriteMarkdown writes r as a Markdown report: the headline stats, the
+// works that gained or lost metadata, the changes with a few example views,
+// and the added and removed views.
+func WriteMarkdown(w io.Writer, r Result) error {
+	var sb strings.Builder
+	writeMarkdown(&sb, r)
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeMarkdown(b *strings.Builder, r Result) {
+	b.WriteString("# Build Diff\n\n")
+	fmt.Fprintf(b, "> Views: %d → %d\n", r.OldViews, r.NewViews)
+	if r.Period.From != "" {
+		fmt.Fprintf(b, "> Stats period: %s – %s\n", r.Period.From, r.Period.To)
+	}
+	if r.IsZero() {
+		b.WriteString("\nNo differences.\n")
+		return
+	}
+
+	b.WriteString("\n## Headline Stats\n\n")
+	b.WriteString("| Metric | Old | New | Change |\n|---|---:|---:|---:|\n")
+	row := func(name string, o, n int) {
+		fmt.Fprintf(b, "| %s | %d | %d | %+d |\n", name, o, n, n-o)
+	}
+	row("Views", r.Old.Views, r.New.Views)
+	row("Estimated minutes", r.Old.DurationMin, r.New.DurationMin)
+	row("Active days", r.Old.ActiveDays, r.New.ActiveDays)
+	row("Views with metadata", r.Old.ResolvedViews, r.New.ResolvedViews)
+	row("Works", r.Old.Works, r.New.Works)
+
+	var shifts []GenreShift
+	for _, g := range r.Stats.GenreShifts {
+		if g.DeltaPt >= 0.05 || g.DeltaPt <= -0.05 {
+			shifts = append(shifts, g)
+		}
+	}
+	if len(shifts) > 0 {
+		b.WriteString("\n### Genre Shares\n\n")
+		b.WriteString("| Genre | Old | New | Change |\n|---|---:|---:|---:|\n")
+		for _, g := range shifts[:min(len(shifts), 10)] {
+			fmt.Fprintf(b, "| %s | %.1f%% | %.1f%% | %+.1fpt |\n", escape(g.Name), g.OldShare, g.NewShare, g.DeltaPt)
+		}
+	}
+
+	writeWorks(b, "Newly Resolved Works", r.Resolved)
+	writeWorks(b, "Newly Unresolved Works", r.Unresolved)
+
+	if len(r.Changes) > 0 {
+		fmt.Fprintf(b, "\n## Changed Views (%d changes)\n\n", len(r.Changes))
+		b.WriteString("| Field | Old | New | Views | Examples |\n|---|---|---|---:|---|\n")
+		for _, c := range r.Changes {
+			var examples []string
+			for _, v := range c.Views[:min(len(c.Views), sampleSize)] {
+				examples = append(examples, v.Date+" "+v.RawTitle)
+			}
+			fmt.Fprintf(b, "| %s | %s | %s | %d | %s |\n", c.Field, escape(c.Old), escape(c.New), len(c.Views), escape(strings.Join(examples, "; ")))
+		}
+	}
+
+	writeViews(b, "Added Views", r.Added)
+	writeViews(b, "Removed Views", r.Removed)
+}
+
+func writeWorks(b *strings.Builder, heading string, works []Work) {
+	if len(works) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n## %s (%d)\n\n", heading, len(works))
+	b.WriteString("| Work | Type | Views | Metadata |\n|---|---|---:|---|\n")
+	for _, w := range works {
+		fmt.Fprintf(b, "| %s | %s | %d | %s |\n", escape(w.Title), w.Type, w.Views, escape(matchLabel(w.Metadata)))
+	}
+}
+
+func writeViews(b *strings.Builder, heading string, items []build.BuiltItem) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n## %s (%d)\n\n", heading, len(items))
+	b.WriteString("| Date | Raw Title |\n|---|---|\n")
+	for _, it := range items {
+		fmt.Fprintf(b, "| %s | %s |\n", it.Date, escape(it.Normalized.RawTitle))
+	}
+}
+
+var escaper = strings.NewReplacer("|", `\|`, "\n", " ")
+
+// escape keeps a value inside its table cell.
+func escape(s string) string {
+	return escaper.Replace(s)
+}
@@ -0,0 +1,138 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/kmdkuk/nfrecap/internal/recap"
+	"github.com/kmdkuk/nfrecap/internal/title"
+)
+
+func item(date, raw string, md *model.Metadata) build.BuiltItem {
+	return build.BuiltItem{Date: date, Normalized: title.Normalize(raw), Metadata: md}
+}
+
+func testBuilds() (build.Built, build.Built) {
+	show := &model.Metadata{Provider: "tmdb", ID: "tv:1", Title: "Show", Genres: []string{"Drama"}, Runtime: 30}
+	film := &model.Metadata{Provider: "tmdb", ID: "movie:2", Title: "Film", Genres: []string{"Comedy"}, Runtime: 100}
+	wrong := &model.Metadata{Provider: "tmdb", ID: "movie:3", Title: "Other Film", Year: 1999, Genres: []string{"Horror"}, Runtime: 90}
+	longer := *show
+	longer.Runtime = 45
+
+	oldBuilt := build.Built{Items: []build.BuiltItem{
+		item("2024-01-01", "Show: シーズン1: 第1話", show),
+		item("2024-01-01", "Show: シーズン1: 第1話", show), // watched twice
+		item("2024-01-02", "Show: シーズン1: 第2話", show),
+		item("2024-01-03", "Film", wrong),
+		item("2024-01-04", "Lost", nil),
+		item("2024-01-05", "Gone", film),
+		item("2024-01-06", "Dropped", nil),
+	}}
+	newBuilt := build.Built{Items: []build.BuiltItem{
+		item("2024-01-01", "Show: シーズン1: 第1話", &longer),
+		item("2024-01-01", "Show: シーズン1: 第1話", &longer),
+		item("2024-01-02", "Show: シーズン1: 第2話", &longer),
+		item("2024-01-03", "Film", film),
+		item("2024-01-04", "Lost", film),
+		item("2024-01-05", "Gone", nil),
+		item("2024-01-07", "Added", nil),
+	}}
+	// a new normalization rule
+	newBuilt.Items[1].Normalized.WorkTitle = "Show!"
+	return oldBuilt, newBuilt
+}
+
+func TestCompare(t *testing.T) {
+	oldBuilt, newBuilt := testBuilds()
+	r := Compare(oldBuilt, newBuilt, Options{})
+	assert.False(t, r.IsZero())
+	assert.Equal(t, 7, r.OldViews)
+	assert.Equal(t, 7, r.NewViews)
+
+	require.Len(t, r.Added, 1)
+	assert.Equal(t, "Added", r.Added[0].Normalized.RawTitle)
+	require.Len(t, r.Removed, 1)
+	assert.Equal(t, "Dropped", r.Removed[0].Normalized.RawTitle)
+
+	require.Len(t, r.Changes, 3)
+	assert.Equal(t, Change{Field: FieldMetadata, Old: "Show / Drama / 30 min", New: "Show / Drama / 45 min", Views: []View{
+		{"2024-01-01", "Show: シーズン1: 第1話"}, {"2024-01-01", "Show: シーズン1: 第1話"}, {"2024-01-02", "Show: シーズン1: 第2話"},
+	}}, r.Changes[0])
+	assert.Equal(t, Change{Field: FieldMatch, Old: "tmdb movie:3 Other Film (1999)", New: "tmdb movie:2 Film", Views: []View{{"2024-01-03", "Film"}}}, r.Changes[1])
+	assert.Equal(t, Change{Field: FieldWorkTitle, Old: "Show", New: "Show!", Views: []View{{"2024-01-01", "Show: シーズン1: 第1話"}}}, r.Changes[2])
+
+	require.Len(t, r.Resolved, 1)
+	assert.Equal(t, Work{Title: "Lost", Type: "movie", Views: 1, Metadata: *newBuilt.Items[4].Metadata}, r.Resolved[0])
+	require.Len(t, r.Unresolved, 1)
+	assert.Equal(t, "Gone", r.Unresolved[0].Title)
+
+	assert.Equal(t, Period{From: "2024-01-01", To: "2024-01-07"}, r.Period)
+	assert.Equal(t, Headline{Views: 7, DurationMin: 3*30 + 90 + 100, ActiveDays: 6, ResolvedViews: 5, Works: 5}, r.Old)
+	assert.Equal(t, Headline{Views: 7, DurationMin: 3*45 + 100 + 100, ActiveDays: 6, ResolvedViews: 5, Works: 6}, r.New)
+	assert.Equal(t, r.New.DurationMin-r.Old.DurationMin, r.Stats.DurationMinDelta)
+}
+
+func TestCompareSame(t *testing.T) {
+	oldBuilt, _ := testBuilds()
+	r := Compare(oldBuilt, oldBuilt, Options{Period: recap.YearPeriod(2024)})
+	assert.True(t, r.IsZero())
+	assert.Equal(t, r.Old, r.New)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteMarkdown(&buf, r))
+	assert.Equal(t, "# Build Diff\n\n> Views: 7 → 7\n> Stats period: 2024-01-01 – 2024-12-31\n\nNo differences.\n", buf.String())
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	oldBuilt, newBuilt := testBuilds()
+	newBuilt.Items[6].Normalized.RawTitle = "A | B"
+	var buf bytes.Buffer
+	require.NoError(t, WriteMarkdown(&buf, Compare(oldBuilt, newBuilt, Options{})))
+	md := buf.String()
+
+	assert.Contains(t, md, "| Estimated minutes | 280 | 335 | +55 |\n")
+	assert.Contains(t, md, "| Works | 5 | 6 | +1 |\n")
+	assert.Contains(t, md, "| Horror | 32.1% | 0.0% | -32.1pt |\n")
+	assert.Contains(t, md, "## Newly Resolved Works (1)\n\n| Work | Type | Views | Metadata |\n|---|---|---:|---|\n| Lost | movie | 1 | tmdb movie:2 Film |\n")
+	assert.Contains(t, md, "## Newly Unresolved Works (1)")
+	assert.Contains(t, md, "| metadata | Show / Drama / 30 min | Show / Drama / 45 min | 3 | 2024-01-01 Show: シーズン1: 第1話; 2024-01-01 Show: シーズン1: 第1話; 2024-01-02 Show: シーズン1: 第2話 |\n")
+	assert.Contains(t, md, "## Added Views (1)\n\n| Date | Raw Title |\n|---|---|\n| 2024-01-07 | A \\| B |\n")
+	assert.Contains(t, md, "## Removed Views (1)")
+}
+
+func TestResultJSONKeys(t *testing.T) {
+	oldBuilt, newBuilt := testBuilds()
+	data, err := json.Marshal(Compare(oldBuilt, newBuilt, Options{}))
+	require.NoError(t, err)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, map[string]any{"from": "2024-01-01", "to": "2024-01-07"}, got["period"])
+
+	stats, ok := got["stats"].(map[string]any)
+	require.True(t, ok)
+	keys := make([]string, 0, len(stats))
+	for k := range stats {
+		keys = append(keys, k)
+	}
+	assert.ElementsMatch(t, []string{
+		"views_delta", "duration_min_delta", "duration_pct", "active_days_delta",
+		"genre_shifts", "new_top_genres", "carried_over_series", "month_shifts",
+	}, keys)
+
+	shifts, ok := stats["genre_shifts"].([]any)
+	require.True(t, ok)
+	require.NotEmpty(t, shifts)
+	assert.Contains(t, shifts[0], "old_share")
+	months, ok := stats["month_shifts"].([]any)
+	require.True(t, ok)
+	require.NotEmpty(t, months)
+	assert.Equal(t, "january", months[0].(map[string]any)["month"])
+	assert.Contains(t, months[0], "old_duration_min")
+}
@@ -0,0 +1,174 @@
+// Package genre maps provider genres onto a canonical, language-independent
+// taxonomy so that e.g. "Action & Adventure" and "アクション" count as the
+// same genre.
+package genre
+
+import (
+	"strings"
+
+	"github.com/kmdkuk/nfrecap/internal/i18n"
+	"github.com/kmdkuk/nfrecap/internal/model"
+)
+
+// Genre is a canonical genre with its display names.
+type Genre struct {
+	Key string
+	Ja  string
+	En  string
+}
+
+// Genres is the canonical taxonomy, in the order used for ties.
+var Genres = []Genre{
+	{Key: "action", Ja: "アクション", En: "Action"},
+	{Key: "adventure", Ja: "アドベンチャー", En: "Adventure"},
+	{Key: "animation", Ja: "アニメーション", En: "Animation"},
+	{Key: "comedy", Ja: "コメディ", En: "Comedy"},
+	{Key: "crime", Ja: "犯罪", En: "Crime"},
+	{Key: "documentary", Ja: "ドキュメンタリー", En: "Documentary"},
+	{Key: "drama", Ja: "ドラマ", En: "Drama"},
+	{Key: "family", Ja: "ファミリー", En: "Family"},
+	{Key: "fantasy", Ja: "ファンタジー", En: "Fantasy"},
+	{Key: "history", Ja: "歴史", En: "History"},
+	{Key: "horror", Ja: "ホラー", En: "Horror"},
+	{Key: "music", Ja: "音楽", En: "Music"},
+	{Key: "mystery", Ja: "ミステリー", En: "Mystery"},
+	{Key: "romance", Ja: "ロマンス", En: "Romance"},
+	{Key: "science_fiction", Ja: "SF", En: "Science Fiction"},
+	{Key: "tv_movie", Ja: "テレビ映画", En: "TV Movie"},
+	{Key: "thriller", Ja: "スリラー", En: "Thriller"},
+	{Key: "war", Ja: "戦争", En: "War"},
+	{Key: "politics", Ja: "政治", En: "Politics"},
+	{Key: "western", Ja: "西部劇", En: "Western"},
+	{Key: "kids", Ja: "キッズ", En: "Kids"},
+	{Key: "news", Ja: "ニュース", En: "News"},
+	{Key: "reality", Ja: "リアリティ", En: "Reality"},
+	{Key: "soap", Ja: "ソープ", En: "Soap"},
+	{Key: "talk", Ja: "トーク", En: "Talk"},
+}
+
+// tmdbIDs maps TMDB movie and tv genre IDs to canonical keys. The combined
+// tv genres expand to both of their parts.
+var tmdbIDs = map[int][]string{
+	28:    {"action"},
+	12:    {"adventure"},
+	16:    {"animation"},
+	35:    {"comedy"},
+	80:    {"crime"},
+	99:    {"documentary"},
+	18:    {"drama"},
+	10751: {"family"},
+	14:    {"fantasy"},
+	36:    {"history"},
+	27:    {"horror"},
+	10402: {"music"},
+	9648:  {"mystery"},
+	10749: {"romance"},
+	878:   {"science_fiction"},
+	10770: {"tv_movie"},
+	53:    {"thriller"},
+	10752: {"war"},
+	37:    {"western"},
+	10759: {"action", "adventure"},
+	10762: {"kids"},
+	10763: {"news"},
+	10764: {"reality"},
+	10765: {"science_fiction", "fantasy"},
+	10766: {"soap"},
+	10767: {"talk"},
+	10768: {"war", "politics"},
+}
+
+// nameAliases maps lower-cased provider genre names that are not display
+// names of the taxonomy (combined tv genres, TMDB's Japanese wording) to
+// canonical keys.
+var nameAliases = map[string][]string{
+	"action & adventure": {"action", "adventure"},
+	"sci-fi & fantasy":   {"science_fiction", "fantasy"},
+	"war & politics":     {"war", "politics"},
+	"サイエンスフィクション":        {"science_fiction"},
+	"履歴":                 {"history"},
+	"謎":                  {"mystery"},
+	"西洋":                 {"western"},
+	"リアリティー":             {"reality"},
+	"アクション&アドベンチャー":      {"action", "adventure"},
+}
+
+var (
+	byKey  = make(map[string]Genre)
+	byName = make(map[string][]string)
+)
+
+func init() {
+	for _, g := range Genres {
+		byKey[g.Key] = g
+		byName[strings.ToLower(g.Ja)] = []string{g.Key}
+		byName[strings.ToLower(g.En)] = []string{g.Key}
+	}
+	for name, keys := range nameAliases {
+		byName[name] = keys
+	}
+}
+
+// Keys returns the canonical keys of md's genres without duplicates.
+// TMDB genre IDs are preferred; otherwise the genre names are matched in
+// either language. Genres outside the taxonomy keep their raw name as key.
+func Keys(md model.Metadata) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	add := func(ks ...string) {
+		for _, k := range ks {
+			if k == "" || seen[k] {
+				continue
+			}
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+
+	if md.Provider == "tmdb" && len(md.GenreIDs) > 0 {
+		for i, id := range md.GenreIDs {
+			if ks, ok := tmdbIDs[id]; ok {
+				add(ks...)
+			} else if i < len(md.Genres) {
+				add(FromName(md.Genres[i])...)
+			}
+		}
+		return keys
+	}
+
+	for _, name := range md.Genres {
+		add(FromName(name)...)
+	}
+	return keys
+}
+
+// FromName returns the canonical keys of a provider genre name.
+func FromName(name string) []string {
+	name = strings.TrimSpace(name)
+	if ks, ok := byName[strings.ToLower(name)]; ok {
+		return ks
+	}
+	return []string{name}
+}
+
+// Known reports whether name is a canonical key, or a name of one in
+// either language.
+func Known(name string) bool {
+	name = strings.TrimSpace(name)
+	_, isKey := byKey[name]
+	_, isName := byName[strings.ToLower(name)]
+	return isKey || isName
+}
+
+// Name returns the display name of key in lang. Unknown keys are returned
+// as is, since they are raw provider names.
+func Name(key, lang string) string {
+	g, ok := byKey[key]
+	if !ok {
+		return key
+	}
+	if lang == i18n.LangEn {
+		return g.En
+	}
+	return g.Ja
+}
@@ -0,0 +1,70 @@
+package genre
+
+import (
+	"testing"
+
+	"github.com/kmdkuk/nfrecap/internal/i18n"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		md   model.Metadata
+		want []string
+	}{
+		{
+			name: "TMDB IDs",
+			md:   model.Metadata{Provider: "tmdb", Genres: []string{"Action & Adventure", "Drama"}, GenreIDs: []int{10759, 18}},
+			want: []string{"action", "adventure", "drama"},
+		},
+		{
+			name: "Unknown ID falls back to the name",
+			md:   model.Metadata{Provider: "tmdb", Genres: []string{"アニメーション", "Anime"}, GenreIDs: []int{16, 99999}},
+			want: []string{"animation", "Anime"},
+		},
+		{
+			name: "English names without IDs",
+			md:   model.Metadata{Provider: "tmdb", Genres: []string{"Sci-Fi & Fantasy", "Fantasy", "comedy"}},
+			want: []string{"science_fiction", "fantasy", "comedy"},
+		},
+		{
+			name: "Japanese names without IDs",
+			md:   model.Metadata{Provider: "tmdb", Genres: []string{"アクション", "サイエンスフィクション", "謎"}},
+			want: []string{"action", "science_fiction", "mystery"},
+		},
+		{
+			name: "No genres",
+			md:   model.Metadata{Provider: "tmdb"},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Keys(tt.md))
+		})
+	}
+}
+
+func TestName(t *testing.T) {
+	assert.Equal(t, "SF", Name("science_fiction", i18n.LangJa))
+	assert.Equal(t, "Science Fiction", Name("science_fiction", i18n.LangEn))
+	assert.Equal(t, "ドラマ", Name("drama", i18n.DefaultLang))
+	assert.Equal(t, "Anime", Name("Anime", i18n.LangJa))
+
+	for _, g := range Genres {
+		assert.Equal(t, []string{g.Key}, FromName(g.En), g.Key)
+		assert.Equal(t, []string{g.Key}, FromName(g.Ja), g.Key)
+	}
+}
+
+func TestKnown(t *testing.T) {
+	for _, name := range []string{"science_fiction", "Science Fiction", "sf", " アニメーション ", "Sci-Fi & Fantasy"} {
+		assert.True(t, Known(name), name)
+	}
+	for _, name := range []string{"Animaton", "Anime", ""} {
+		assert.False(t, Known(name), name)
+	}
+}
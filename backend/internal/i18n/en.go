@@ -0,0 +1,285 @@
+package i18n
+
+import "fmt"
+
+var en = catalog{
+	dateLayout:     "Jan 2, 2006",
+	dateTimeLayout: "Jan 2, 2006 15:04",
+	groupDigits:    true,
+	months:         [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	yearMonth: func(year int, month string) string {
+		return fmt.Sprintf("%s %d", month, year)
+	},
+	weekdays: [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	messages: map[string]string{
+		// Common
+		"none":  "none",
+		"rank":  "#%d",
+		"days":  "%d days",
+		"span":  "%s – %s",
+		"hour":  "%d:00",
+		"ratio": "%s×",
+		"other": "Other",
+
+		// Ranking keys
+		"sort.duration": "Watch Time",
+		"sort.views":    "Views",
+
+		// Devices
+		"device.tv":      "TV",
+		"device.phone":   "Smartphone",
+		"device.tablet":  "Tablet",
+		"device.browser": "Browser / PC",
+		"device.other":   "Other",
+		"device.unknown": "Unknown",
+
+		// Built-in holidays
+		"holiday.golden_week": "Golden Week",
+		"holiday.obon":        "Obon",
+		"holiday.new_year":    "New Year holidays",
+
+		// Charts
+		"chart.day":     "%s: %d views / %s hours",
+		"chart.weekday": "%s: %d views / %s hours",
+		"chart.genre":   "%s: %s hours (%s%%)",
+
+		// Table columns
+		"col.metric":               "Metric",
+		"col.change":               "Change",
+		"col.change_pt":            "Change (pt)",
+		"col.change_hours":         "Change (hours)",
+		"col.hours":                "Est. watch time (hours)",
+		"col.hours_of":             "%s (hours)",
+		"col.views":                "Views",
+		"col.active_days":          "Active days",
+		"col.genre":                "Genre",
+		"col.month":                "Month",
+		"col.year":                 "Year",
+		"col.weekday":              "Weekday",
+		"col.rank":                 "Rank",
+		"col.week":                 "Week",
+		"col.span":                 "Period",
+		"col.days":                 "Days",
+		"col.daily_avg_min":        "Daily avg (min)",
+		"col.ratio_to_usual":       "vs. usual days",
+		"col.hour":                 "Hour",
+		"col.device":               "Device",
+		"col.share":                "Share",
+		"col.weekday_hours":        "Weekdays (hours)",
+		"col.weekend_hours":        "Weekends (hours)",
+		"col.top_genres":           "Favorite genres",
+		"col.top_genre":            "Top genre",
+		"col.top_series":           "Top series",
+		"col.streak_days":          "Streak",
+		"col.peak_month":           "Peak month",
+		"col.peak_hours":           "Peak (hours)",
+		"col.note":                 "Note",
+		"col.samples":              "Sample titles (%d)",
+		"col.title":                "Title",
+		"col.type":                 "Type",
+		"col.series":               "Series",
+		"col.series_span":          "Watched between",
+		"col.episodes":             "Episodes",
+		"col.episodes_before_drop": "Episodes before dropping",
+		"col.finished_on":          "Finished on",
+		"col.completion":           "Completion",
+		"col.last_watched":         "Last watched",
+		"col.rewatches":            "Rewatches",
+		"col.rewatched_episodes":   "Episodes rewatched",
+		"col.first_watched":        "First watched",
+		"col.value":                "Value",
+		"col.description":          "Description",
+		"col.works":                "Works",
+		"col.new_works":            "New works",
+		"col.new_rate":             "New",
+		"col.raw_titles":           "Titles in the viewing history",
+
+		// Header & TL;DR
+		"header.generated_at": "Generated at: %s",
+		"header.period":       "Period: %s – %s (%d days)",
+		"tldr.title":          "At a Glance (TL;DR)",
+		"tldr.total_hours":    "Estimated watch time: **%s hours** (%s min)",
+		"tldr.views":          "Views: **%s**",
+		"tldr.active_days":    "Active days: **%d days** (%s%%)",
+		"tldr.longest_streak": "Longest streak: **%d days** (%s – %s)",
+		"tldr.coverage":       "Metadata coverage: **%s / %s** (%s%%)",
+
+		// Comparison
+		"compare.title":               "Comparison: %s → %s",
+		"compare.pct":                 "%s (%s%%)",
+		"compare.genres":              "Genre Mix Changes",
+		"compare.new_top_genres":      "Genres new to the top: %s",
+		"compare.carried_over_series": "Series watched in both periods: %s",
+		"compare.months":              "Months That Changed Most",
+
+		// 1. Overview
+		"overview.title":              "1. Overview",
+		"monthly.title":               "Watch Time and Views by Month",
+		"weekday.title":               "Viewing by Weekday",
+		"note.runtime":                "Note: Estimated watch time is based on `runtime_min`",
+		"note.runtime_tv":             "Note: For TV series, a typical episode runtime is used",
+		"note.runtime_activity":       "Note: When built from the account's viewing activity (ViewingActivity.csv), the actual playback time is used",
+		"weekly.title":                "Weeks, Weekdays and Weekends",
+		"weekly.busiest":              "Busiest week: **%s** (%s) %s hours",
+		"weekly.daily_avg":            "Average watch time per day: weekdays **%s min** / weekends **%s min**",
+		"holidays.title":              "Viewing on Holidays",
+		"holidays.note":               "Note: Compared with the daily average of days outside any holiday period (%s min)",
+		"time_of_day.title":           "Viewing by Time of Day (estimated hours)",
+		"time_of_day.late_night":      "Late-night views (%d:00–%d:00): **%d** (%s%%)",
+		"time_of_day.sessions":        "Viewing sessions: **%d** (%s min on average)",
+		"time_of_day.longest_session": "Longest session: **%d min** (%s – %s, %d views)",
+		"time_of_day.note_timed":      "Note: Based on the %d views with a recorded start time",
+		"time_of_day.note_session":    "Note: A view starting within %d minutes after the previous one ended belongs to the same session",
+		"devices.title":               "Viewing by Device",
+		"devices.note":                "Note: Devices are classified from the Device Type of the account's viewing activity (ViewingActivity.csv)",
+
+		// 2. Streaks
+		"streaks.chapter":      "2. Viewing Streaks",
+		"streaks.longest":      "Longest Streak",
+		"streaks.longest_days": "**%d days in a row**",
+		"streaks.longest_span": "Period: %s – %s",
+		"streaks.ranking":      "Streak Ranking (Top %d)",
+		"streaks.gaps":         "Gaps Between Viewing",
+		"streaks.active_days":  "Days with viewing: %d",
+		"streaks.max_gap":      "Longest break: %d days",
+		"streaks.max_gap_span": "(%s – %s)",
+		"calendar.title":       "Viewing Calendar",
+		"calendar.note":        "Note: Darker days mean longer estimated watch time (open in a viewer that renders SVG)",
+
+		// 3. Genres
+		"genres.chapter_duration":  "3. Genres (by watch time)",
+		"genres.title":             "Estimated Watch Time by Genre (Top %d + Other)",
+		"genres.spikes":            "Genre Peaks by Month",
+		"genre_trends.title":       "Genre Trends (monthly share)",
+		"genre_trends.description": "Share of each month's estimated watch time per top genre (titles with several genres count for each, so the total can exceed 100%).",
+
+		// 4. Titles & series
+		"titles.chapter":                   "4. Titles and Series",
+		"genre_samples.title":              "Movies Watched by Genre (samples)",
+		"titles.title":                     "Top Titles by %s (Top %d)",
+		"series.title":                     "Top Series by %s (TV only)",
+		"completion.title":                 "Series Completion",
+		"completion.summary":               "Finished: **%d** / In progress: **%d** / Dropped: **%d**",
+		"completion.finished":              "Series Finished in the Period",
+		"completion.in_progress":           "Series in Progress",
+		"completion.abandoned":             "Dropped Series",
+		"completion.note_episodes":         "Note: Completion is based on the episode counts per season on TMDB (specials excluded)",
+		"completion.note_abandoned":        "Note: Unfinished series not watched for %d days or more are counted as dropped",
+		"rewatches.title":                  "Rewatches",
+		"rewatches.summary":                "First-time: **%d views** / Repeat: **%d views** (%s%%)",
+		"rewatches.note":                   "Note: A view is a repeat when the same movie or episode was already watched on an earlier day",
+		"habits.title":                     "Viewing Style (diversity and habits)",
+		"habits.diversity":                 "Genre diversity",
+		"habits.diversity_value":           "%s bit (evenness %s%%)",
+		"habits.diversity_description":     "Shannon entropy of the estimated watch time per genre. Higher means a wider range of genres; 100% evenness means equal time for every genre",
+		"habits.concentration":             "Top %d titles' share",
+		"habits.concentration_description": "Share of the estimated watch time spent on the top %d titles. Higher means you focused on a few titles",
+		"habits.discovery":                 "Discovery",
+		"habits.discovery_value":           "%d / %d works (%s%%)",
+		"habits.discovery_description":     "Share of the works watched in the period that you watched for the first time",
+		"habits.one_and_done":              "One-episode series",
+		"habits.one_and_done_value":        "%d",
+		"habits.one_and_done_description":  "Series with only one episode watched and no follow-up for %d days or more",
+		"habits.discovery_monthly":         "New Works by Month",
+		"habits.one_and_done_list":         "One-episode series: %s",
+
+		// 5. Data quality & notes
+		"quality.chapter":           "5. Data Quality and Notes",
+		"quality.title":             "Metadata Coverage",
+		"quality.covered":           "Resolved: **%s / %s** (%s%%)",
+		"quality.unresolved":        "Unresolved: %d",
+		"quality.unresolved_title":  "Unresolved Titles (by views)",
+		"quality.appendix_included": "Note: Every unresolved work and its titles in the viewing history are listed in the appendix",
+		"quality.appendix_hint":     "Note: Enable the `unresolved_appendix` section to list every unresolved work with its titles in the viewing history",
+		"notes.title":               "Notes",
+		"notes.generated_by":        "Generated with [https://github.com/kmdkuk/nfrecap](https://github.com/kmdkuk/nfrecap).",
+		"notes.tmdb":                "Titles are identified and their metadata fetched with [https://www.themoviedb.org/](https://www.themoviedb.org/).",
+		"notes.estimate":            "Estimated watch time is approximate and may differ from the actual playback time.",
+		"notes.unresolved":          "Unresolved titles may be resolved by future normalization rules or manual fixes.",
+		"appendix.title":            "Appendix: Unresolved Titles",
+		"appendix.description":      "Works without metadata and the titles recorded in the viewing history, as a reference for normalization rules and manual fixes.",
+
+		// All-time report
+		"alltime.period":             "Period: %s – %s",
+		"alltime.active_days":        "Active days: **%d days**",
+		"alltime.active_ratio":       "%d (%s%%)",
+		"alltime.years":              "1. Year by Year",
+		"alltime.rankings":           "2. All-Time Rankings",
+		"alltime.titles_by_duration": "Top Titles by Watch Time",
+		"alltime.titles_by_views":    "Top Titles by Views",
+		"alltime.records":            "3. All-Time Records",
+		"alltime.max_gap":            "Longest break: %d days (%s – %s)",
+
+		// HTML report
+		"html.summary":          "At a Glance",
+		"html.card_hours":       "Estimated watch time (hours)",
+		"html.card_views":       "Views",
+		"html.card_active_days": "Active days (%s%%)",
+		"html.card_streak":      "Longest streak (days)",
+		"html.card_coverage":    "Metadata coverage",
+		"html.monthly":          "Estimated Watch Time by Month",
+		"html.weekday":          "Estimated Watch Time by Weekday",
+		"html.calendar_note":    "Darker days mean longer estimated watch time.",
+		"html.genres":           "Estimated Watch Time by Genre",
+		"html.genres_note":      "Titles with several genres count for each, so shares can add up to more than 100%.",
+		"html.titles":           "Top Titles by %s",
+		"html.title_stats":      "%s hours / %d views",
+		"html.series":           "Top Series by %s",
+		"html.col_series":       "Series",
+		"html.col_series_span":  "Watched",
+		"html.quality":          "Data Quality",
+		"html.coverage":         "Metadata coverage: %s / %s (%s%%) / unresolved: %d",
+
+		// Share cards
+		"card.total_hours":    "Estimated watch time",
+		"card.hours_unit":     "hours",
+		"card.top_genre":      "Top genre",
+		"card.top_series":     "Top series",
+		"card.longest_streak": "Longest streak",
+		"card.monthly":        "Watch time by month",
+		"card.footer":         "%s views · %d active days",
+
+		// Mermaid charts
+		"mermaid.series_spans": "Series Viewing Spans",
+
+		// Terminal dashboard
+		"tui.tab.overview": "Overview",
+		"tui.tab.months":   "Months",
+		"tui.tab.genres":   "Genres",
+		"tui.tab.titles":   "Titles",
+		"tui.tab.series":   "Series",
+		"tui.month_days":   "Days of %s",
+		"tui.genre_titles": "Titles in %s",
+		"tui.col.day":      "Day",
+		"tui.empty":        "No data",
+		"tui.help":         "←/→ tabs  ↑/↓ move  Enter details  [/] previous/next year  q quit",
+		"tui.help_drill":   "↑/↓ move  Esc back  [/] previous/next year  q quit",
+
+		// Calendar export
+		"ics.calendar_name":  "Netflix viewing history",
+		"ics.summary_tv":     "%s (%d ep.)",
+		"ics.views":          "Views: %d",
+		"ics.minutes":        "Estimated watch time: %d min",
+		"ics.episodes":       "Episodes watched:",
+		"ics.original_title": "Title: %s",
+		"ics.year":           "Released: %d",
+		"ics.genres":         "Genres: %s",
+		"ics.runtime":        "Runtime: %d min",
+		"ics.total_episodes": "Seasons: %d / Episodes: %d",
+		"ics.source":         "Metadata: %s %s",
+		"ics.unresolved":     "No metadata",
+
+		// Filtered recaps
+		"header.filter":       "Filter: %s",
+		"filter.type_movie":   "Movies",
+		"filter.type_tv":      "TV",
+		"filter.type_unknown": "Unknown type",
+		"filter.genres":       "Genre %s",
+		"filter.title":        "Title contains \"%s\"",
+		"filter.title_regex":  "Title matches /%s/",
+		"filter.providers":    "Provider %s",
+		"filter.resolved":     "Resolved only",
+		"filter.unresolved":   "Unresolved only",
+		"filter.excluded":     "Excluded titles: %d",
+	},
+}
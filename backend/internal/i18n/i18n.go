@@ -0,0 +1,155 @@
+// Package i18n holds the message catalogs of the reports and formats
+// numbers and dates for each language.
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	LangJa = "ja"
+	LangEn = "en"
+
+	// DefaultLang is used when no language is configured.
+	DefaultLang = LangJa
+)
+
+// Langs lists the supported languages.
+var Langs = []string{LangJa, LangEn}
+
+// catalog is everything that differs between languages.
+type catalog struct {
+	dateLayout     string
+	dateTimeLayout string
+	groupDigits    bool // 1,234 instead of 1234
+	months         [12]string
+	yearMonth      func(year int, month string) string
+	weekdays       [7]string // Sunday first
+	messages       map[string]string
+}
+
+var catalogs = map[string]*catalog{
+	LangJa: &ja,
+	LangEn: &en,
+}
+
+// Supported reports whether lang has a catalog.
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Printer formats messages, numbers and dates in one language.
+type Printer struct {
+	lang string
+	c    *catalog
+}
+
+// New returns a printer for lang, or for DefaultLang if lang is empty.
+func New(lang string) (*Printer, error) {
+	if lang == "" {
+		lang = DefaultLang
+	}
+	c, ok := catalogs[lang]
+	if !ok {
+		return nil, fmt.Errorf("unsupported language %q: must be one of %s", lang, strings.Join(Langs, ", "))
+	}
+	return &Printer{lang: lang, c: c}, nil
+}
+
+// For is New for validated languages; unsupported ones get DefaultLang.
+func For(lang string) *Printer {
+	p, err := New(lang)
+	if err != nil {
+		p, _ = New(DefaultLang)
+	}
+	return p
+}
+
+func (p *Printer) Lang() string {
+	return p.lang
+}
+
+// T returns the message for key formatted with args as by fmt.Sprintf.
+// Messages missing from the catalog fall back to DefaultLang, then to key.
+func (p *Printer) T(key string, args ...any) string {
+	msg, ok := p.c.messages[key]
+	if !ok {
+		if msg, ok = catalogs[DefaultLang].messages[key]; !ok {
+			return key
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Int formats n, e.g. "1,234" in English.
+func (p *Printer) Int(n int) string {
+	return p.Float(float64(n), 0)
+}
+
+// Float formats f with a fixed number of decimals.
+func (p *Printer) Float(f float64, decimals int) string {
+	s := strconv.FormatFloat(f, 'f', decimals, 64)
+	if !p.c.groupDigits {
+		return s
+	}
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	intPart, frac, hasFrac := strings.Cut(s, ".")
+	var b strings.Builder
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	if hasFrac {
+		b.WriteString("." + frac)
+	}
+	return sign + b.String()
+}
+
+// Signed is Float with a leading "+" for non-negative values.
+func (p *Printer) Signed(f float64, decimals int) string {
+	s := p.Float(f, decimals)
+	if !strings.HasPrefix(s, "-") {
+		s = "+" + s
+	}
+	return s
+}
+
+// Hours formats minutes as hours with one decimal.
+func (p *Printer) Hours(minutes int) string {
+	return p.Float(float64(minutes)/60.0, 1)
+}
+
+func (p *Printer) Date(t time.Time) string {
+	return t.Format(p.c.dateLayout)
+}
+
+func (p *Printer) DateTime(t time.Time) string {
+	return t.Format(p.c.dateTimeLayout)
+}
+
+// Month returns the short name of m, e.g. "1月" or "Jan".
+func (p *Printer) Month(m time.Month) string {
+	return p.c.months[m-1]
+}
+
+// YearMonth returns e.g. "2025年1月" or "Jan 2025".
+func (p *Printer) YearMonth(year int, m time.Month) string {
+	return p.c.yearMonth(year, p.Month(m))
+}
+
+// Weekday returns the short name of wd, e.g. "日" or "Sun".
+func (p *Printer) Weekday(wd time.Weekday) string {
+	return p.c.weekdays[wd]
+}
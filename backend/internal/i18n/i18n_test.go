@@ -0,0 +1,55 @@
+package i18n
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrinter(t *testing.T) {
+	ja, err := New("")
+	require.NoError(t, err)
+	assert.Equal(t, LangJa, ja.Lang())
+	en, err := New(LangEn)
+	require.NoError(t, err)
+	_, err = New("fr")
+	assert.ErrorContains(t, err, `unsupported language "fr": must be one of ja, en`)
+	assert.Equal(t, LangJa, For("fr").Lang())
+
+	assert.Equal(t, "3位", ja.T("rank", 3))
+	assert.Equal(t, "#3", en.T("rank", 3))
+	assert.Equal(t, "その他", ja.T("other"))
+	assert.Equal(t, "no.such.key", en.T("no.such.key"))
+
+	assert.Equal(t, "1234567", ja.Int(1234567))
+	assert.Equal(t, "1,234,567", en.Int(1234567))
+	assert.Equal(t, "-1,234.50", en.Float(-1234.5, 2))
+	assert.Equal(t, "999", en.Int(999))
+	assert.Equal(t, "+1,000.0", en.Signed(1000, 1))
+	assert.Equal(t, "-0.5", ja.Signed(-0.5, 1))
+	assert.Equal(t, "2.5", ja.Hours(150))
+
+	d := time.Date(2025, time.March, 9, 21, 5, 0, 0, time.UTC)
+	assert.Equal(t, "2025-03-09", ja.Date(d))
+	assert.Equal(t, "Mar 9, 2025", en.Date(d))
+	assert.Equal(t, "2025-03-09 21:05", ja.DateTime(d))
+	assert.Equal(t, "3月", ja.Month(d.Month()))
+	assert.Equal(t, "2025年3月", ja.YearMonth(2025, d.Month()))
+	assert.Equal(t, "Mar 2025", en.YearMonth(2025, d.Month()))
+	assert.Equal(t, "日", ja.Weekday(d.Weekday()))
+	assert.Equal(t, "Sun", en.Weekday(d.Weekday()))
+}
+
+// Every catalog must translate every message of the default catalog.
+func TestCatalogsComplete(t *testing.T) {
+	for lang, c := range catalogs {
+		for key := range catalogs[DefaultLang].messages {
+			assert.Contains(t, c.messages, key, "%s: %s", lang, key)
+		}
+		for key := range c.messages {
+			assert.Contains(t, catalogs[DefaultLang].messages, key, "%s: %s", lang, key)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package i18n
+
+import "fmt"
+
+var ja = catalog{
+	dateLayout:     "2006-01-02",
+	dateTimeLayout: "2006-01-02 15:04",
+	months:         [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+	yearMonth: func(year int, month string) string {
+		return fmt.Sprintf("%d年%s", year, month)
+	},
+	weekdays: [7]string{"日", "月", "火", "水", "木", "金", "土"},
+	messages: map[string]string{
+		// Common
+		"none":  "なし",
+		"rank":  "%d位",
+		"days":  "%d 日",
+		"span":  "%s 〜 %s",
+		"hour":  "%d時",
+		"ratio": "%s 倍",
+		"other": "その他",
+
+		// Ranking keys
+		"sort.duration": "推定視聴時間",
+		"sort.views":    "視聴回数",
+
+		// Devices
+		"device.tv":      "テレビ",
+		"device.phone":   "スマートフォン",
+		"device.tablet":  "タブレット",
+		"device.browser": "ブラウザ・PC",
+		"device.other":   "その他",
+		"device.unknown": "不明",
+
+		// Built-in holidays
+		"holiday.golden_week": "ゴールデンウィーク",
+		"holiday.obon":        "お盆",
+		"holiday.new_year":    "年末年始",
+
+		// Charts
+		"chart.day":     "%s: %d 本 / %s 時間",
+		"chart.weekday": "%s曜日: %d 本 / %s 時間",
+		"chart.genre":   "%s: %s 時間（%s%%）",
+
+		// Table columns
+		"col.metric":               "指標",
+		"col.change":               "増減",
+		"col.change_pt":            "増減（pt）",
+		"col.change_hours":         "増減（時間）",
+		"col.hours":                "推定視聴時間（時間）",
+		"col.hours_of":             "%s（時間）",
+		"col.views":                "視聴回数",
+		"col.active_days":          "視聴日数",
+		"col.genre":                "ジャンル",
+		"col.month":                "月",
+		"col.year":                 "年",
+		"col.weekday":              "曜日",
+		"col.rank":                 "順位",
+		"col.week":                 "週",
+		"col.span":                 "期間",
+		"col.days":                 "日数",
+		"col.daily_avg_min":        "1日平均（分）",
+		"col.ratio_to_usual":       "通常日比",
+		"col.hour":                 "時間帯",
+		"col.device":               "デバイス",
+		"col.share":                "割合",
+		"col.weekday_hours":        "平日（時間）",
+		"col.weekend_hours":        "週末（時間）",
+		"col.top_genres":           "よく見るジャンル",
+		"col.top_genre":            "最多ジャンル",
+		"col.top_series":           "最多シリーズ",
+		"col.streak_days":          "連続日数",
+		"col.peak_month":           "最多視聴月",
+		"col.peak_hours":           "ピーク時間（時間）",
+		"col.note":                 "備考",
+		"col.samples":              "代表的な視聴作品（%d選）",
+		"col.title":                "作品名",
+		"col.type":                 "種別",
+		"col.series":               "シリーズ名",
+		"col.series_span":          "集中視聴期間",
+		"col.episodes":             "視聴話数",
+		"col.episodes_before_drop": "離脱までの話数",
+		"col.finished_on":          "完走日",
+		"col.completion":           "完走率",
+		"col.last_watched":         "最終視聴日",
+		"col.rewatches":            "リピート回数",
+		"col.rewatched_episodes":   "リピートした話数",
+		"col.first_watched":        "初回視聴日",
+		"col.value":                "値",
+		"col.description":          "説明",
+		"col.works":                "視聴作品数",
+		"col.new_works":            "新規作品数",
+		"col.new_rate":             "新規率",
+		"col.raw_titles":           "視聴履歴上のタイトル",
+
+		// Header & TL;DR
+		"header.generated_at": "生成日時: %s",
+		"header.period":       "対象期間: %s 〜 %s（%d 日間）",
+		"tldr.title":          "まずは要点（TL;DR）",
+		"tldr.total_hours":    "推定総視聴時間：**%s 時間**（%s 分）",
+		"tldr.views":          "視聴回数：**%s 本**",
+		"tldr.active_days":    "視聴日数：**%d 日**（%s%%）",
+		"tldr.longest_streak": "最長連続視聴：**%d 日**（%s 〜 %s）",
+		"tldr.coverage":       "メタデータ取得率：**%s / %s**（%s%%）",
+
+		// Comparison
+		"compare.title":               "比較：%s → %s",
+		"compare.pct":                 "%s（%s%%）",
+		"compare.genres":              "ジャンル構成の変化",
+		"compare.new_top_genres":      "新たに上位に入ったジャンル：%s",
+		"compare.carried_over_series": "継続して視聴したシリーズ：%s",
+		"compare.months":              "変化の大きかった月",
+
+		// 1. Overview
+		"overview.title":              "1. 全体概要",
+		"monthly.title":               "月別 推定視聴時間・視聴回数",
+		"weekday.title":               "曜日別 視聴傾向",
+		"note.runtime":                "※ 推定視聴時間は `runtime_min` を基に算出しています",
+		"note.runtime_tv":             "※ TV シリーズの場合、1話あたりの代表的な再生時間を使用しています",
+		"note.runtime_activity":       "※ アカウントの視聴履歴（ViewingActivity.csv）から作成した場合は実際の再生時間を使用しています",
+		"weekly.title":                "週別・平日／週末",
+		"weekly.busiest":              "最も視聴した週：**%s**（%s）%s 時間",
+		"weekly.daily_avg":            "1日あたりの平均視聴時間：平日 **%s 分** / 週末 **%s 分**",
+		"holidays.title":              "休暇期間の視聴",
+		"holidays.note":               "※ 通常日比は、いずれの休暇期間にも含まれない日の1日平均（%s 分）との比です",
+		"time_of_day.title":           "時間帯別 視聴傾向（推定視聴時間・時間）",
+		"time_of_day.late_night":      "深夜視聴（%d時〜%d時）：**%d 回**（%s%%）",
+		"time_of_day.sessions":        "視聴セッション数：**%d 回**（平均 %s 分）",
+		"time_of_day.longest_session": "最長セッション：**%d 分**（%s 〜 %s、%d 本）",
+		"time_of_day.note_timed":      "※ 開始時刻が記録されている %d 件を対象にしています",
+		"time_of_day.note_session":    "※ 前の視聴の終了から %d 分以内に始まった視聴は同じセッションとみなしています",
+		"devices.title":               "デバイス別 視聴傾向",
+		"devices.note":                "※ デバイス種別はアカウントの視聴履歴（ViewingActivity.csv）の Device Type から分類しています",
+
+		// 2. Streaks
+		"streaks.chapter":      "2. 視聴の継続性（Streak）",
+		"streaks.longest":      "最長連続視聴記録",
+		"streaks.longest_days": "**%d 日連続**",
+		"streaks.longest_span": "期間：%s 〜 %s",
+		"streaks.ranking":      "連続視聴ランキング（Top %d）",
+		"streaks.gaps":         "視聴活動の間隔",
+		"streaks.active_days":  "視聴があった日数：%d 日",
+		"streaks.max_gap":      "最長の空白期間：%d 日",
+		"streaks.max_gap_span": "（%s 〜 %s）",
+		"calendar.title":       "視聴カレンダー",
+		"calendar.note":        "※ 色が濃いほどその日の推定視聴時間が長いことを示します（SVG を表示できるビューアで確認してください）",
+
+		// 3. Genres
+		"genres.chapter_duration":  "3. ジャンル別分析（時間ベース）",
+		"genres.title":             "ジャンル別 推定視聴時間（Top %d + その他）",
+		"genres.spikes":            "ジャンルの偏り（月別ピーク）",
+		"genre_trends.title":       "ジャンルの推移（月別シェア）",
+		"genre_trends.description": "各月の推定視聴時間に占める、上位ジャンルの割合です（複数ジャンルの作品は各ジャンルに計上するため、合計は 100% を超えることがあります）。",
+
+		// 4. Titles & series
+		"titles.chapter":                   "4. 作品・シリーズ別",
+		"genre_samples.title":              "ジャンル別視聴映画（サンプル）",
+		"titles.title":                     "%sが多い作品（Top %d）",
+		"series.title":                     "%sが多いシリーズ（TV作品のみ）",
+		"completion.title":                 "シリーズの完走状況",
+		"completion.summary":               "完走：**%d 作品** / 視聴中：**%d 作品** / 離脱：**%d 作品**",
+		"completion.finished":              "期間内に完走したシリーズ",
+		"completion.in_progress":           "視聴中のシリーズ",
+		"completion.abandoned":             "離脱したシリーズ",
+		"completion.note_episodes":         "※ 完走率は TMDB のシーズンごとの話数（特別編を除く）を基に算出しています",
+		"completion.note_abandoned":        "※ 最終視聴から %d 日以上経過した未完走シリーズを「離脱」としています",
+		"rewatches.title":                  "繰り返し視聴（リウォッチ）",
+		"rewatches.summary":                "初見：**%d 回** / リピート：**%d 回**（%s%%）",
+		"rewatches.note":                   "※ 同じ映画・同じエピソードを別の日にもう一度視聴した場合をリピートとしています",
+		"habits.title":                     "視聴スタイル（多様性・習慣）",
+		"habits.diversity":                 "ジャンルの多様性",
+		"habits.diversity_value":           "%s bit（均等度 %s%%）",
+		"habits.diversity_description":     "ジャンル別の推定視聴時間のシャノンエントロピーです。大きいほど幅広いジャンルを見ており、均等度 100% はすべてのジャンルを同じ時間ずつ見た状態です",
+		"habits.concentration":             "上位 %d 作品への集中度",
+		"habits.concentration_description": "推定視聴時間のうち、上位 %d 作品が占める割合です。高いほど特定の作品をじっくり見ています",
+		"habits.discovery":                 "新しい作品との出会い",
+		"habits.discovery_value":           "%d / %d 作品（%s%%）",
+		"habits.discovery_description":     "期間内に見た作品のうち、初めて見た作品の割合です",
+		"habits.one_and_done":              "1話だけで離れたシリーズ",
+		"habits.one_and_done_value":        "%d 作品",
+		"habits.one_and_done_description":  "1話だけ見て、その後 %d 日以上続きを見ていないシリーズの数です",
+		"habits.discovery_monthly":         "月別 新規作品の割合",
+		"habits.one_and_done_list":         "1話だけで離れたシリーズ：%s",
+
+		// 5. Data quality & notes
+		"quality.chapter":           "5. データ品質・補足",
+		"quality.title":             "メタデータ取得状況",
+		"quality.covered":           "取得済み：**%s / %s**（%s%%）",
+		"quality.unresolved":        "未取得（unresolved）：%d 件",
+		"quality.unresolved_title":  "未取得の作品（視聴回数順）",
+		"quality.appendix_included": "※ すべての未取得作品と視聴履歴上のタイトルは付録に掲載しています",
+		"quality.appendix_hint":     "※ `unresolved_appendix` セクションを有効にすると、すべての未取得作品と視聴履歴上のタイトルを付録に出力できます",
+		"notes.title":               "注記",
+		"notes.generated_by":        "本文章は [https://github.com/kmdkuk/nfrecap](https://github.com/kmdkuk/nfrecap) を利用して生成されました。",
+		"notes.tmdb":                "作品の特定およびメタデータ取得には [https://www.themoviedb.org/](https://www.themoviedb.org/) を利用しています。",
+		"notes.estimate":            "推定視聴時間は参考値であり、実際の再生時間と一致しない場合があります。",
+		"notes.unresolved":          "未取得作品は、今後の正規化ルール改善や手動補正で解消できる可能性があります。",
+		"appendix.title":            "付録：未取得作品の一覧",
+		"appendix.description":      "メタデータを取得できなかった作品と、視聴履歴に記録されていたタイトルの一覧です。正規化ルールの改善や手動補正の参考にしてください。",
+
+		// All-time report
+		"alltime.period":             "対象期間: %s 〜 %s",
+		"alltime.active_days":        "視聴日数：**%d 日**",
+		"alltime.active_ratio":       "%d（%s%%）",
+		"alltime.years":              "1. 年別推移",
+		"alltime.rankings":           "2. 通算ランキング",
+		"alltime.titles_by_duration": "推定視聴時間が多い作品（Top）",
+		"alltime.titles_by_views":    "視聴回数が多い作品（Top）",
+		"alltime.records":            "3. 通算記録",
+		"alltime.max_gap":            "最長の空白期間：%d 日（%s 〜 %s）",
+
+		// HTML report
+		"html.summary":          "まずは要点",
+		"html.card_hours":       "推定総視聴時間（時間）",
+		"html.card_views":       "視聴回数（本）",
+		"html.card_active_days": "視聴日数（%s%%）",
+		"html.card_streak":      "最長連続視聴（日）",
+		"html.card_coverage":    "メタデータ取得率",
+		"html.monthly":          "月別の推定視聴時間",
+		"html.weekday":          "曜日別の推定視聴時間",
+		"html.calendar_note":    "色が濃いほどその日の推定視聴時間が長いことを示します。",
+		"html.genres":           "ジャンル別 推定視聴時間",
+		"html.genres_note":      "1 本が複数のジャンルに数えられるため、割合の合計は 100% を超えることがあります。",
+		"html.titles":           "%sが多い作品",
+		"html.title_stats":      "%s 時間 ／ %d 回",
+		"html.series":           "%sが多いシリーズ",
+		"html.col_series":       "シリーズ",
+		"html.col_series_span":  "視聴期間",
+		"html.quality":          "データ品質",
+		"html.coverage":         "メタデータ取得率：%s / %s（%s%%）／ 未取得：%d 件",
+
+		// Share cards
+		"card.total_hours":    "推定視聴時間",
+		"card.hours_unit":     "時間",
+		"card.top_genre":      "いちばん観たジャンル",
+		"card.top_series":     "いちばん観たシリーズ",
+		"card.longest_streak": "最長連続視聴",
+		"card.monthly":        "月別の推定視聴時間",
+		"card.footer":         "%s 本視聴 / 視聴日数 %d 日",
+
+		// Mermaid charts
+		"mermaid.series_spans": "シリーズの視聴期間",
+
+		// Terminal dashboard
+		"tui.tab.overview": "概要",
+		"tui.tab.months":   "月別",
+		"tui.tab.genres":   "ジャンル",
+		"tui.tab.titles":   "作品",
+		"tui.tab.series":   "シリーズ",
+		"tui.month_days":   "%sの日別",
+		"tui.genre_titles": "%sの作品",
+		"tui.col.day":      "日付",
+		"tui.empty":        "データがありません",
+		"tui.help":         "←/→ タブ  ↑/↓ 移動  Enter 詳細  [/] 前年/翌年  q 終了",
+		"tui.help_drill":   "↑/↓ 移動  Esc 戻る  [/] 前年/翌年  q 終了",
+
+		// Calendar export
+		"ics.calendar_name":  "Netflix 視聴履歴",
+		"ics.summary_tv":     "%s（%d 話）",
+		"ics.views":          "視聴回数：%d",
+		"ics.minutes":        "推定視聴時間：%d 分",
+		"ics.episodes":       "視聴したエピソード：",
+		"ics.original_title": "作品名：%s",
+		"ics.year":           "公開年：%d",
+		"ics.genres":         "ジャンル：%s",
+		"ics.runtime":        "再生時間：%d 分",
+		"ics.total_episodes": "シーズン数：%d / 話数：%d",
+		"ics.source":         "メタデータ：%s %s",
+		"ics.unresolved":     "メタデータ未取得",
+
+		// Filtered recaps
+		"header.filter":       "絞り込み: %s",
+		"filter.type_movie":   "映画",
+		"filter.type_tv":      "TV",
+		"filter.type_unknown": "種別不明",
+		"filter.genres":       "ジャンル %s",
+		"filter.title":        "タイトルに「%s」を含む",
+		"filter.title_regex":  "タイトルが /%s/ に一致",
+		"filter.providers":    "取得元 %s",
+		"filter.resolved":     "メタデータあり",
+		"filter.unresolved":   "メタデータなし",
+		"filter.excluded":     "除外タイトル %d 件",
+	},
+}
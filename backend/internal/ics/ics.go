@@ -0,0 +1,290 @@
+// Package ics exports the viewing history as an iCalendar (RFC 5545) file
+// of all-day events, to overlay it on a personal calendar.
+package ics
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/genre"
+	"github.com/kmdkuk/nfrecap/internal/i18n"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/kmdkuk/nfrecap/internal/recap"
+)
+
+// How views of a title are grouped into events.
+const (
+	// GroupDay makes one event per title per day.
+	GroupDay = "day"
+	// GroupBinge makes one event per title per binge: a run of consecutive
+	// days with views of the title.
+	GroupBinge = "binge"
+)
+
+const dateLayout = "2006-01-02"
+
+// Options controls Write.
+type Options struct {
+	Group  string       // GroupDay (default) or GroupBinge
+	Period recap.Period // only views in the period; zero means every view
+	Lang   string       // language of summaries and descriptions, i18n.DefaultLang if empty
+}
+
+// Validate checks the group and language.
+func (o Options) Validate() error {
+	if o.Group != "" && o.Group != GroupDay && o.Group != GroupBinge {
+		return fmt.Errorf("invalid group %q: must be %q or %q", o.Group, GroupDay, GroupBinge)
+	}
+	if o.Lang != "" {
+		if _, err := i18n.New(o.Lang); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Event is one all-day calendar event.
+type Event struct {
+	UID         string
+	Summary     string
+	Start       time.Time // first day
+	End         time.Time // last day, inclusive
+	Description string
+	Categories  []string // genre names
+}
+
+// work collects the views of one title.
+type work struct {
+	title    string
+	typ      string
+	metadata *model.Metadata
+	days     map[time.Time][]build.BuiltItem
+}
+
+// Events groups the views of built into events, ordered by start date and
+// title.
+func Events(built build.Built, opts Options) []Event {
+	if opts.Group == "" {
+		opts.Group = GroupDay
+	}
+	p := i18n.For(opts.Lang)
+
+	works := make(map[string]*work) // "Title|Type" -> views
+	for _, it := range built.Items {
+		d, err := time.Parse(dateLayout, it.Date)
+		if err != nil {
+			continue
+		}
+		if !opts.Period.From.IsZero() && !opts.Period.Contains(d) {
+			continue
+		}
+		key := it.Normalized.WorkTitle + "|" + it.Normalized.Type
+		w, ok := works[key]
+		if !ok {
+			w = &work{title: it.Normalized.WorkTitle, typ: it.Normalized.Type, days: make(map[time.Time][]build.BuiltItem)}
+			works[key] = w
+		}
+		if w.metadata == nil {
+			w.metadata = it.Metadata
+		}
+		w.days[d] = append(w.days[d], it)
+	}
+
+	var events []Event
+	for _, w := range works {
+		days := make([]time.Time, 0, len(w.days))
+		for d := range w.days {
+			days = append(days, d)
+		}
+		sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+		for start := 0; start < len(days); {
+			end := start
+			if opts.Group == GroupBinge {
+				for end+1 < len(days) && days[end+1].Equal(days[end].AddDate(0, 0, 1)) {
+					end++
+				}
+			}
+			events = append(events, w.event(p, days[start:end+1]))
+			start = end + 1
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].Start.Equal(events[j].Start) {
+			return events[i].Start.Before(events[j].Start)
+		}
+		return events[i].Summary < events[j].Summary
+	})
+	return events
+}
+
+// event makes the event of the views of w on days.
+func (w *work) event(p *i18n.Printer, days []time.Time) Event {
+	first, last := days[0], days[len(days)-1]
+	var items []build.BuiltItem
+	for _, d := range days {
+		items = append(items, w.days[d]...)
+	}
+
+	e := Event{
+		UID:     uid(w.title, w.typ, first),
+		Summary: w.title,
+		Start:   first,
+		End:     last,
+	}
+	if w.typ == "tv" {
+		e.Summary = p.T("ics.summary_tv", w.title, len(items))
+	}
+
+	minutes := 0
+	for _, it := range items {
+		minutes += it.Minutes()
+	}
+	lines := []string{
+		p.T("ics.views", len(items)),
+		p.T("ics.minutes", minutes),
+	}
+
+	if w.typ == "tv" {
+		lines = append(lines, "", p.T("ics.episodes"))
+		for _, it := range items {
+			ep := episodeLabel(it.Normalized)
+			if len(days) > 1 {
+				ep = it.Date + " " + ep
+			}
+			lines = append(lines, "- "+ep)
+		}
+	}
+
+	lines = append(lines, "")
+	if md := w.metadata; md != nil {
+		for _, k := range genre.Keys(*md) {
+			e.Categories = append(e.Categories, genre.Name(k, p.Lang()))
+		}
+		if md.Title != "" && md.Title != w.title {
+			lines = append(lines, p.T("ics.original_title", md.Title))
+		}
+		if md.Year > 0 {
+			lines = append(lines, p.T("ics.year", md.Year))
+		}
+		if len(e.Categories) > 0 {
+			lines = append(lines, p.T("ics.genres", strings.Join(e.Categories, ", ")))
+		}
+		if md.Runtime > 0 {
+			lines = append(lines, p.T("ics.runtime", md.Runtime))
+		}
+		if n := md.TotalEpisodes(); n > 0 {
+			lines = append(lines, p.T("ics.total_episodes", len(md.Seasons), n))
+		}
+		if md.Provider != "" {
+			lines = append(lines, p.T("ics.source", md.Provider, md.ID))
+		}
+	} else {
+		lines = append(lines, p.T("ics.unresolved"))
+	}
+	e.Description = strings.Join(lines, "\n")
+	return e
+}
+
+// episodeLabel is the season and episode as in the viewing history, e.g.
+// "シーズン1: 第1話", or the raw title if the title has neither.
+func episodeLabel(n model.NormalizedTitle) string {
+	var parts []string
+	for _, s := range []string{n.Season, n.EpisodeTitle} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	if len(parts) == 0 {
+		return n.RawTitle
+	}
+	return strings.Join(parts, ": ")
+}
+
+// uid is stable across exports, so re-importing a file updates events
+// rather than duplicating them.
+func uid(title, typ string, start time.Time) string {
+	h := sha1.Sum([]byte(title + "|" + typ + "|" + start.Format(dateLayout)))
+	return hex.EncodeToString(h[:8]) + "@nfrecap"
+}
+
+// Write writes the events of built as an iCalendar file. DTSTAMP is the
+// build time of built, so the same input gives the same file.
+func Write(w io.Writer, built build.Built, opts Options) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	stamp, err := time.Parse(time.RFC3339, built.GeneratedAt)
+	if err != nil {
+		stamp = time.Now()
+	}
+	p := i18n.For(opts.Lang)
+
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		fold(bw, name+":"+value)
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//kmdkuk//nfrecap//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", escape(p.T("ics.calendar_name")))
+	for _, e := range Events(built, opts) {
+		line("BEGIN", "VEVENT")
+		line("UID", e.UID)
+		line("DTSTAMP", stamp.UTC().Format("20060102T150405Z"))
+		line("DTSTART;VALUE=DATE", e.Start.Format("20060102"))
+		line("DTEND;VALUE=DATE", e.End.AddDate(0, 0, 1).Format("20060102")) // exclusive
+		line("SUMMARY", escape(e.Summary))
+		line("DESCRIPTION", escape(e.Description))
+		if len(e.Categories) > 0 {
+			cats := make([]string, len(e.Categories))
+			for i, c := range e.Categories {
+				cats[i] = escape(c)
+			}
+			line("CATEGORIES", strings.Join(cats, ","))
+		}
+		line("TRANSP", "TRANSPARENT") // viewing does not make you busy
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// fold writes a content line, folded into lines of at most 75 octets
+// without splitting a UTF-8 character, each ending in CRLF.
+func fold(w *bufio.Writer, s string) {
+	const limit = 75
+	for first := true; ; first = false {
+		n := limit
+		if !first {
+			n-- // room for the leading space
+			w.WriteByte(' ')
+		}
+		if len(s) <= n {
+			w.WriteString(s + "\r\n")
+			return
+		}
+		for n > 0 && !utf8.RuneStart(s[n]) {
+			n--
+		}
+		w.WriteString(s[:n] + "\r\n")
+		s = s[n:]
+	}
+}
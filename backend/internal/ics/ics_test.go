@@ -0,0 +1,120 @@
+package ics
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/kmdkuk/nfrecap/internal/recap"
+	"github.com/kmdkuk/nfrecap/internal/title"
+)
+
+func testBuilt() build.Built {
+	show := &model.Metadata{Provider: "tmdb", ID: "tv:1", Title: "Show", Year: 2020, Genres: []string{"Drama"}, Runtime: 30,
+		Seasons: []model.Season{{Number: 1, EpisodeCount: 8}}}
+	film := &model.Metadata{Provider: "tmdb", ID: "movie:2", Title: "Film", Genres: []string{"Comedy", "Drama"}, Runtime: 100}
+	return build.Built{GeneratedAt: "2024-12-31T09:00:00+09:00", Items: []build.BuiltItem{
+		{Date: "2024-01-01", Normalized: title.Normalize("Show: シーズン1: 第1話"), Metadata: show},
+		{Date: "2024-01-01", Normalized: title.Normalize("Show: シーズン1: 第2話"), Metadata: show},
+		{Date: "2024-01-02", Normalized: title.Normalize("Show: シーズン1: 第3話"), Metadata: show, DurationSec: 20 * 60},
+		{Date: "2024-01-05", Normalized: title.Normalize("Show: シーズン1: 第4話"), Metadata: show},
+		{Date: "2024-01-02", Normalized: title.Normalize("Film"), Metadata: film},
+		{Date: "2023-12-31", Normalized: title.Normalize("Lost; Film, Part 1")},
+	}}
+}
+
+func TestEventsByDay(t *testing.T) {
+	events := Events(testBuilt(), Options{Lang: "en"})
+	require.Len(t, events, 5)
+
+	assert.Equal(t, "Lost; Film, Part 1", events[0].Summary)
+	assert.Contains(t, events[0].Description, "No metadata")
+
+	e := events[1]
+	assert.Equal(t, "Show (2 ep.)", e.Summary)
+	assert.Equal(t, "2024-01-01", e.Start.Format(dateLayout))
+	assert.Equal(t, e.Start, e.End)
+	assert.Equal(t, []string{"Drama"}, e.Categories)
+	assert.Equal(t, strings.Join([]string{
+		"Views: 2",
+		"Estimated watch time: 60 min",
+		"",
+		"Episodes watched:",
+		"- シーズン1: 第1話",
+		"- シーズン1: 第2話",
+		"",
+		"Released: 2020",
+		"Genres: Drama",
+		"Runtime: 30 min",
+		"Seasons: 1 / Episodes: 8",
+		"Metadata: tmdb tv:1",
+	}, "\n"), e.Description)
+
+	// same day, ordered by title
+	assert.Equal(t, "Film", events[2].Summary)
+	assert.Equal(t, []string{"Comedy", "Drama"}, events[2].Categories)
+	assert.Equal(t, "Show (1 ep.)", events[3].Summary)
+	assert.Contains(t, events[3].Description, "Estimated watch time: 20 min")
+}
+
+func TestEventsByBinge(t *testing.T) {
+	events := Events(testBuilt(), Options{Group: GroupBinge, Period: recap.YearPeriod(2024)})
+	require.Len(t, events, 3)
+
+	e := events[0]
+	assert.Equal(t, "Show（3 話）", e.Summary)
+	assert.Equal(t, "2024-01-01", e.Start.Format(dateLayout))
+	assert.Equal(t, "2024-01-02", e.End.Format(dateLayout))
+	assert.Contains(t, e.Description, "- 2024-01-02 シーズン1: 第3話")
+	assert.Contains(t, e.Description, "ジャンル：ドラマ")
+
+	assert.Equal(t, "Film", events[1].Summary)
+	assert.Equal(t, "Show（1 話）", events[2].Summary)
+	assert.Equal(t, "2024-01-05", events[2].Start.Format(dateLayout))
+
+	// UIDs are stable and unique
+	again := Events(testBuilt(), Options{Group: GroupBinge, Period: recap.YearPeriod(2024)})
+	assert.Equal(t, e.UID, again[0].UID)
+	assert.NotEqual(t, e.UID, events[2].UID)
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, testBuilt(), Options{Lang: "en"}))
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+	assert.Equal(t, 5, strings.Count(out, "BEGIN:VEVENT\r\n"))
+	assert.Contains(t, out, "DTSTAMP:20241231T000000Z\r\n")
+	assert.Contains(t, out, "DTSTART;VALUE=DATE:20240101\r\nDTEND;VALUE=DATE:20240102\r\n")
+	assert.Contains(t, out, `SUMMARY:Lost\; Film\, Part 1`+"\r\n")
+	assert.Contains(t, out, "CATEGORIES:Comedy,Drama\r\n")
+
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	assert.Contains(t, unfolded, `DESCRIPTION:Views: 2\nEstimated watch time: 60 min\n`)
+	sc := bufio.NewScanner(strings.NewReader(out))
+	for sc.Scan() {
+		assert.LessOrEqual(t, len(sc.Bytes()), 75, sc.Text())
+	}
+
+	assert.ErrorContains(t, Write(&buf, testBuilt(), Options{Group: "week"}), `invalid group "week"`)
+}
+
+func TestFold(t *testing.T) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	fold(w, "SUMMARY:"+strings.Repeat("あ", 30))
+	require.NoError(t, w.Flush())
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	require.Len(t, lines, 2)
+	assert.Len(t, lines[0], 8+3*22) // 74 octets, no split character
+	assert.Equal(t, " "+strings.Repeat("あ", 8), lines[1])
+}
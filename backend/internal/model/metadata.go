@@ -1,10 +1,27 @@
 package model
 
 type Metadata struct {
-	Provider string   `json:"provider"`
-	ID       string   `json:"id"`
-	Title    string   `json:"title"`
-	Year     int      `json:"year,omitempty"`
-	Genres   []string `json:"genres,omitempty"`
-	Runtime  int      `json:"runtime_min,omitempty"` // movie runtime or avg episode runtime
+	Provider   string   `json:"provider"`
+	ID         string   `json:"id"`
+	Title      string   `json:"title"`
+	Year       int      `json:"year,omitempty"`
+	Genres     []string `json:"genres,omitempty"`
+	GenreIDs   []int    `json:"genre_ids,omitempty"`   // provider genre IDs, aligned with Genres
+	Runtime    int      `json:"runtime_min,omitempty"` // movie runtime or avg episode runtime
+	Seasons    []Season `json:"seasons,omitempty"`     // tv only, specials (season 0) excluded
+	PosterPath string   `json:"poster_path,omitempty"` // provider image path, e.g. "/abc.jpg"
+}
+
+type Season struct {
+	Number       int `json:"number"`
+	EpisodeCount int `json:"episode_count"`
+}
+
+// TotalEpisodes returns the number of episodes across all regular seasons.
+func (m Metadata) TotalEpisodes() int {
+	total := 0
+	for _, s := range m.Seasons {
+		total += s.EpisodeCount
+	}
+	return total
 }
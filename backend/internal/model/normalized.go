@@ -1,9 +1,11 @@
 package model
 
 type NormalizedTitle struct {
-	RawTitle     string `json:"raw_title"`
-	WorkTitle    string `json:"work_title"`
-	Type         string `json:"type"` // "movie" | "tv" | "unknown"
-	Season       string `json:"season,omitempty"`
-	EpisodeTitle string `json:"episode_title,omitempty"`
+	RawTitle      string `json:"raw_title"`
+	WorkTitle     string `json:"work_title"`
+	Type          string `json:"type"` // "movie" | "tv" | "unknown"
+	Season        string `json:"season,omitempty"`
+	EpisodeTitle  string `json:"episode_title,omitempty"`
+	SeasonNumber  int    `json:"season_number,omitempty"`  // parsed from Season, 0 if unknown
+	EpisodeNumber int    `json:"episode_number,omitempty"` // parsed from EpisodeTitle, 0 if unknown
 }
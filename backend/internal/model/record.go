@@ -5,4 +5,9 @@ import "time"
 type ViewingRecord struct {
 	Title string
 	Date  time.Time // date only
+
+	// Only available from the account export (ViewingActivity.csv)
+	StartTime time.Time // zero if unknown
+	Duration  time.Duration
+	Device    string // "Device Type" as exported, e.g. "Sony PS4"
 }
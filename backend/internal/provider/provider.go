@@ -2,6 +2,10 @@ package provider
 
 import "github.com/kmdkuk/nfrecap/internal/model"
 
+// Provider resolves a normalized work title to external metadata.
+// For tv works, implementations should fill Metadata.Seasons with the
+// episode count of each regular season so that series completion can be
+// computed from cached data alone.
 type Provider interface {
 	Lookup(workTitle string, typ string) (model.Metadata, bool, error)
 }
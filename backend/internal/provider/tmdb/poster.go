@@ -0,0 +1,55 @@
+package tmdbprovider
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// PosterBaseURL serves TMDB poster images at a width suited for reports.
+const PosterBaseURL = "https://image.tmdb.org/t/p/w185"
+
+var posterClient = &http.Client{Timeout: 30 * time.Second}
+
+// DownloadPoster saves the poster at posterPath (e.g. "/abc.jpg", as in
+// model.Metadata.PosterPath) into dir under its base name. Existing files are
+// kept, so it reports whether it downloaded anything.
+func DownloadPoster(posterPath, dir string) (bool, error) {
+	name := filepath.Base(posterPath)
+	if posterPath == "" || name == "." || name == "/" {
+		return false, nil
+	}
+	dst := filepath.Join(dir, name)
+	if _, err := os.Stat(dst); err == nil {
+		return false, nil
+	}
+
+	resp, err := posterClient.Get(PosterBaseURL + "/" + name)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("failed to download poster %s: %s", name, resp.Status)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return false, err
+	}
+	tmp, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		return false, err
+	}
+	if err := tmp.Close(); err != nil {
+		return false, err
+	}
+	return true, os.Rename(tmp.Name(), dst)
+}
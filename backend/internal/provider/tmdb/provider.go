@@ -113,16 +113,20 @@ func (p *Provider) Lookup(workTitle string, typ string) (model.Metadata, bool, e
 		}
 
 		genres := make([]string, len(details.Genres))
+		genreIDs := make([]int, len(details.Genres))
 		for i, g := range details.Genres {
 			genres[i] = g.Name
+			genreIDs[i] = int(g.ID)
 		}
 
 		return model.Metadata{
-			Provider: "tmdb",
-			ID:       fmt.Sprintf("movie:%d", id),
-			Title:    details.Title,
-			Genres:   genres,
-			Runtime:  int(details.Runtime),
+			Provider:   "tmdb",
+			ID:         fmt.Sprintf("movie:%d", id),
+			Title:      details.Title,
+			Genres:     genres,
+			GenreIDs:   genreIDs,
+			Runtime:    int(details.Runtime),
+			PosterPath: details.PosterPath,
 		}, true, nil
 
 	} else { // tv
@@ -132,8 +136,10 @@ func (p *Provider) Lookup(workTitle string, typ string) (model.Metadata, bool, e
 		}
 
 		genres := make([]string, len(details.Genres))
+		genreIDs := make([]int, len(details.Genres))
 		for i, g := range details.Genres {
 			genres[i] = g.Name
+			genreIDs[i] = int(g.ID)
 		}
 
 		// TV usually has "episode_run_time" (array) or we can just not set Runtime if ambiguous.
@@ -143,12 +149,27 @@ func (p *Provider) Lookup(workTitle string, typ string) (model.Metadata, bool, e
 			runtime = int(details.EpisodeRunTime[0])
 		}
 
+		// Season 0 holds specials, which are not part of the regular run.
+		var seasons []model.Season
+		for _, s := range details.Seasons {
+			if s.SeasonNumber <= 0 {
+				continue
+			}
+			seasons = append(seasons, model.Season{
+				Number:       s.SeasonNumber,
+				EpisodeCount: s.EpisodeCount,
+			})
+		}
+
 		return model.Metadata{
-			Provider: "tmdb",
-			ID:       fmt.Sprintf("tv:%d", id),
-			Title:    details.Name,
-			Genres:   genres,
-			Runtime:  runtime,
+			Provider:   "tmdb",
+			ID:         fmt.Sprintf("tv:%d", id),
+			Title:      details.Name,
+			Genres:     genres,
+			GenreIDs:   genreIDs,
+			Runtime:    runtime,
+			Seasons:    seasons,
+			PosterPath: details.PosterPath,
 		}, true, nil
 	}
 }
@@ -0,0 +1,113 @@
+// Package query lists the views of a built JSON selected by a build.Filter,
+// as an aligned table or CSV. JSON output is the filtered build.Built itself,
+// so it can be fed back into `nfrecap recap`.
+package query
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/genre"
+)
+
+// Row is one view, flattened for tables and CSV.
+type Row struct {
+	Date     string
+	Title    string // work title
+	Type     string
+	Season   string
+	Episode  string
+	Genres   []string // display names
+	Provider string
+	ID       string
+	Minutes  int
+}
+
+// Columns are the CSV header, in the order of Row.
+var Columns = []string{"date", "title", "type", "season", "episode", "genres", "provider", "id", "minutes"}
+
+// Rows flattens the views of built, with genre names in lang.
+func Rows(built build.Built, lang string) []Row {
+	rows := make([]Row, 0, len(built.Items))
+	for _, it := range built.Items {
+		r := Row{
+			Date:    it.Date,
+			Title:   it.Normalized.WorkTitle,
+			Type:    it.Normalized.Type,
+			Season:  it.Normalized.Season,
+			Episode: it.Normalized.EpisodeTitle,
+			Minutes: it.Minutes(),
+		}
+		if md := it.Metadata; md != nil {
+			for _, k := range genre.Keys(*md) {
+				r.Genres = append(r.Genres, genre.Name(k, lang))
+			}
+			r.Provider = md.Provider
+			r.ID = md.ID
+		}
+		rows = append(rows, r)
+	}
+	return rows
+}
+
+func (r Row) fields() []string {
+	return []string{r.Date, r.Title, r.Type, r.Season, r.Episode, strings.Join(r.Genres, ", "), r.Provider, r.ID, strconv.Itoa(r.Minutes)}
+}
+
+// WriteCSV writes rows with a header line.
+func WriteCSV(w io.Writer, rows []Row) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(Columns); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if err := cw.Write(r.fields()); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// maxCellWidth cuts long titles so that a table stays readable.
+const maxCellWidth = 40
+
+// WriteTable writes rows as a table aligned for the terminal, wide (e.g.
+// Japanese) characters included, followed by the totals.
+func WriteTable(w io.Writer, rows []Row) error {
+	cells := [][]string{Columns}
+	minutes := 0
+	for _, r := range rows {
+		cells = append(cells, r.fields())
+		minutes += r.Minutes
+	}
+	widths := make([]int, len(Columns))
+	for _, row := range cells {
+		for i, c := range row {
+			widths[i] = min(max(widths[i], runewidth.StringWidth(c)), maxCellWidth)
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range cells {
+		for i, c := range row {
+			c = runewidth.Truncate(c, widths[i], "…")
+			if i == len(row)-1 {
+				// minutes, right-aligned
+				b.WriteString(runewidth.FillLeft(c, widths[i]))
+			} else {
+				b.WriteString(runewidth.FillRight(c, widths[i]) + "  ")
+			}
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "\n%d views, %d min (%.1f h)\n", len(rows), minutes, float64(minutes)/60)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,62 @@
+package query
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/kmdkuk/nfrecap/internal/title"
+)
+
+func testBuilt() build.Built {
+	show := &model.Metadata{Provider: "tmdb", ID: "tv:1", Title: "Show", Genres: []string{"Animation", "Drama"}, Runtime: 25}
+	return build.Built{Items: []build.BuiltItem{
+		{Date: "2025-01-02", Normalized: title.Normalize("ショー: シーズン1: 第1話"), Metadata: show, DurationSec: 20 * 60},
+		{Date: "2025-01-01", Normalized: title.Normalize("Film, Part 1")},
+	}}
+}
+
+func TestRows(t *testing.T) {
+	rows := Rows(testBuilt(), "en")
+	require.Len(t, rows, 2)
+	assert.Equal(t, Row{Date: "2025-01-02", Title: "ショー", Type: "tv", Season: "シーズン1", Episode: "第1話",
+		Genres: []string{"Animation", "Drama"}, Provider: "tmdb", ID: "tv:1", Minutes: 20}, rows[0])
+	assert.Equal(t, "Film, Part 1", rows[1].Title)
+	assert.Empty(t, rows[1].Genres)
+
+	assert.Equal(t, []string{"アニメーション", "ドラマ"}, Rows(testBuilt(), "ja")[0].Genres)
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, Rows(testBuilt(), "en")))
+	assert.Equal(t, strings.Join([]string{
+		"date,title,type,season,episode,genres,provider,id,minutes",
+		`2025-01-02,ショー,tv,シーズン1,第1話,"Animation, Drama",tmdb,tv:1,20`,
+		`2025-01-01,"Film, Part 1",movie,,,,,,0`,
+		"",
+	}, "\n"), buf.String())
+}
+
+func TestWriteTable(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteTable(&buf, Rows(testBuilt(), "en")))
+	lines := strings.Split(buf.String(), "\n")
+	require.Len(t, lines, 6)
+
+	// columns line up, counting wide characters as two cells
+	assert.Equal(t, strings.Index(lines[0], "type"), strings.Index(lines[2], "movie"))
+	assert.Equal(t, "2025-01-02  ショー", lines[1][:len("2025-01-02  ショー")])
+	assert.True(t, strings.HasSuffix(lines[1], " 20"))
+	assert.Equal(t, "2 views, 20 min (0.3 h)", lines[4])
+
+	rows := []Row{{Title: strings.Repeat("x", 50)}}
+	buf.Reset()
+	require.NoError(t, WriteTable(&buf, rows))
+	assert.Contains(t, buf.String(), strings.Repeat("x", maxCellWidth-1)+"…")
+}
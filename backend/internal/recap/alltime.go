@@ -0,0 +1,96 @@
+package recap
+
+import (
+	"slices"
+	"time"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+)
+
+type AllTimeStats struct {
+	GeneratedAt string
+	Years       []YearSummary
+
+	// Lifetime covers the first to the last viewing day, so streaks and
+	// gaps spanning a new year are not cut at the year boundary.
+	Lifetime Stats
+}
+
+type YearSummary struct {
+	Year        int
+	Views       int
+	DurationMin int
+	ActiveDays  int
+	ActiveRatio float64 // percent of days in the year
+	TopGenre    string
+	TopSeries   string
+}
+
+// ComputeAllTime aggregates every year found in built into a trend table
+// plus lifetime rankings and streak records.
+func ComputeAllTime(built build.Built) AllTimeStats {
+	return ComputeAllTimeWithOptions(built, Options{})
+}
+
+// Years returns the years with at least one view in built, in order.
+func Years(built build.Built) []int {
+	var years []int
+	for _, it := range built.Items {
+		d, err := time.Parse(dateLayout, it.Date)
+		if err != nil {
+			continue
+		}
+		if !slices.Contains(years, d.Year()) {
+			years = append(years, d.Year())
+		}
+	}
+	slices.Sort(years)
+	return years
+}
+
+// ComputeAllTimeWithOptions is ComputeAllTime with the settings of opts;
+// opts.Period is ignored.
+func ComputeAllTimeWithOptions(built build.Built, opts Options) AllTimeStats {
+	a := AllTimeStats{GeneratedAt: built.GeneratedAt}
+	built = opts.Filter.Apply(built) // so that years without matching views are left out
+
+	var first, last time.Time
+	for _, it := range built.Items {
+		d, err := time.Parse(dateLayout, it.Date)
+		if err != nil {
+			continue
+		}
+		if first.IsZero() || d.Before(first) {
+			first = d
+		}
+		if last.IsZero() || d.After(last) {
+			last = d
+		}
+	}
+	if first.IsZero() {
+		return a
+	}
+
+	for y := first.Year(); y <= last.Year(); y++ {
+		opts.Period = YearPeriod(y)
+		s := ComputeStatsWithOptions(built, opts)
+		ys := YearSummary{
+			Year:        y,
+			Views:       s.TotalViews,
+			DurationMin: s.TotalDurationMin,
+			ActiveDays:  s.ActiveDays,
+			ActiveRatio: float64(s.ActiveDays) / float64(s.PeriodDays) * 100,
+		}
+		if len(s.GenreStats) > 0 {
+			ys.TopGenre = s.GenreStats[0].Name
+		}
+		if len(s.TopSeriesByDuration) > 0 {
+			ys.TopSeries = s.TopSeriesByDuration[0].SeriesName
+		}
+		a.Years = append(a.Years, ys)
+	}
+
+	opts.Period = Period{From: first, To: last}
+	a.Lifetime = ComputeStatsWithOptions(built, opts)
+	return a
+}
@@ -0,0 +1,59 @@
+package recap
+
+import (
+	"testing"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeAllTime(t *testing.T) {
+	item := func(date, title, typ string, runtime int, genres ...string) build.BuiltItem {
+		return build.BuiltItem{
+			Date:       date,
+			Normalized: model.NormalizedTitle{WorkTitle: title, Type: typ},
+			Metadata:   &model.Metadata{Runtime: runtime, Genres: genres},
+		}
+	}
+	built := build.Built{Items: []build.BuiltItem{
+		item("2022-12-30", "Show", "tv", 30, "Drama"),
+		item("2022-12-31", "Show", "tv", 30, "Drama"),
+		item("2023-01-01", "Movie", "movie", 120, "Action"),
+		// nothing in 2024
+		item("2025-05-05", "Movie", "movie", 120, "Action"),
+		{Date: "broken"},
+	}}
+
+	a := ComputeAllTime(built)
+
+	if assert.Len(t, a.Years, 4) {
+		assert.Equal(t, YearSummary{Year: 2022, Views: 2, DurationMin: 60, ActiveDays: 2, ActiveRatio: 2.0 / 365 * 100, TopGenre: "ドラマ", TopSeries: "Show"}, a.Years[0])
+		assert.Equal(t, "アクション", a.Years[1].TopGenre)
+		assert.Equal(t, "", a.Years[1].TopSeries)
+		assert.Equal(t, 0, a.Years[2].Views)
+		assert.Equal(t, 1, a.Years[3].Views)
+	}
+
+	assert.Equal(t, 4, a.Lifetime.TotalViews)
+	if assert.NotEmpty(t, a.Lifetime.TopStreaks) {
+		// streak crosses the new year
+		assert.Equal(t, 3, a.Lifetime.TopStreaks[0].Days)
+		assert.Equal(t, "2022-12-30", a.Lifetime.TopStreaks[0].Start.Format(dateLayout))
+	}
+	if assert.NotEmpty(t, a.Lifetime.TopTitlesByDuration) {
+		assert.Equal(t, "Movie", a.Lifetime.TopTitlesByDuration[0].Title)
+		assert.Equal(t, 240, a.Lifetime.TopTitlesByDuration[0].DurationMin)
+	}
+
+	md, err := RenderAllTimeMarkdown(a)
+	require.NoError(t, err)
+	assert.Contains(t, md, "| 2024 | 0 | 0.0 | 0（0.0%） | - | - |")
+}
+
+func TestComputeAllTime_Empty(t *testing.T) {
+	a := ComputeAllTime(build.Built{})
+	assert.Empty(t, a.Years)
+	assert.Equal(t, 0, a.Lifetime.TotalViews)
+}
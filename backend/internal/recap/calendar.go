@@ -0,0 +1,39 @@
+package recap
+
+import (
+	"time"
+)
+
+type DayMetric struct {
+	Date time.Time
+	Metric
+}
+
+// CompactCalendar is the per-day series in a compact form for API clients:
+// Views[i] and Minutes[i] belong to Start + i days.
+type CompactCalendar struct {
+	Start   string `json:"start"`
+	Views   []int  `json:"views"`
+	Minutes []int  `json:"minutes"`
+}
+
+// computeDaily builds one entry per day of the period, including idle days.
+func (s *Stats) computeDaily(days map[time.Time]Metric) {
+	s.Daily = make([]DayMetric, 0, s.PeriodDays)
+	for d := s.Period.From; !d.After(s.Period.To); d = d.AddDate(0, 0, 1) {
+		s.Daily = append(s.Daily, DayMetric{Date: d, Metric: days[d]})
+	}
+}
+
+func NewCompactCalendar(s Stats) CompactCalendar {
+	c := CompactCalendar{
+		Start:   s.Period.From.Format(dateLayout),
+		Views:   make([]int, len(s.Daily)),
+		Minutes: make([]int, len(s.Daily)),
+	}
+	for i, d := range s.Daily {
+		c.Views[i] = d.Views
+		c.Minutes[i] = d.DurationMin
+	}
+	return c
+}
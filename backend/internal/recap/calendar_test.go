@@ -0,0 +1,60 @@
+package recap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDailyAndCalendar(t *testing.T) {
+	item := func(date string, runtime int) build.BuiltItem {
+		return build.BuiltItem{Date: date, Normalized: model.NormalizedTitle{WorkTitle: "A"}, Metadata: &model.Metadata{Runtime: runtime}}
+	}
+	items := []build.BuiltItem{
+		item("2024-01-01", 30),
+		item("2024-01-01", 60),
+		item("2024-02-29", 120),
+		item("2025-01-01", 10), // outside the period
+	}
+
+	s := ComputeStats(build.Built{Items: items}, 2024)
+
+	if assert.Len(t, s.Daily, 366) {
+		assert.Equal(t, "2024-01-01", s.Daily[0].Date.Format(dateLayout))
+		assert.Equal(t, Metric{Views: 2, DurationMin: 90}, s.Daily[0].Metric)
+		assert.Equal(t, Metric{}, s.Daily[1].Metric)
+		assert.Equal(t, Metric{Views: 1, DurationMin: 120}, s.Daily[59].Metric)
+		assert.Equal(t, "2024-12-31", s.Daily[365].Date.Format(dateLayout))
+	}
+
+	c := NewCompactCalendar(s)
+	assert.Equal(t, "2024-01-01", c.Start)
+	assert.Len(t, c.Views, 366)
+	assert.Equal(t, 2, c.Views[0])
+	assert.Equal(t, 120, c.Minutes[59])
+
+	svg := RenderCalendarSVG(s)
+	assert.True(t, strings.HasPrefix(svg, "<svg "))
+	assert.Equal(t, 366, strings.Count(svg, "<rect "))
+	assert.Contains(t, svg, "<title>2024-02-29: 1 本 / 2.0 時間</title>")
+	assert.NotContains(t, svg, "\n\n", "blank lines would end the HTML block in Markdown")
+}
+
+func TestCalLevel(t *testing.T) {
+	days := []DayMetric{
+		{Metric: Metric{Views: 1, DurationMin: 10}},
+		{Metric: Metric{Views: 1, DurationMin: 20}},
+		{Metric: Metric{Views: 1, DurationMin: 30}},
+		{Metric: Metric{Views: 1, DurationMin: 40}},
+		{},
+	}
+	th := calThresholds(days)
+	assert.Equal(t, []int{20, 30, 40}, th)
+	assert.Equal(t, 0, calLevel(days[4], th))
+	assert.Equal(t, 1, calLevel(days[0], th))
+	assert.Equal(t, 2, calLevel(days[1], th))
+	assert.Equal(t, 4, calLevel(days[3], th))
+}
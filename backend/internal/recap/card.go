@@ -0,0 +1,330 @@
+package recap
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// cardFontTTF is M+ 1p Regular (see fonts/LICENSE), which covers Latin, kana
+// and kanji, so cards render the same on every machine without system fonts.
+//
+//go:embed fonts/mplus-1p-regular.ttf
+var cardFontTTF []byte
+
+var loadCardFont = sync.OnceValues(func() (*opentype.Font, error) {
+	return opentype.Parse(cardFontTTF)
+})
+
+// CardSize is the pixel size of a summary card.
+type CardSize struct {
+	Name   string
+	Width  int
+	Height int
+}
+
+var (
+	// CardStory is portrait, for stories on Instagram and the like.
+	CardStory = CardSize{Name: "story", Width: 1080, Height: 1920}
+	// CardWide is the 1.91:1 link preview size of X and Facebook.
+	CardWide = CardSize{Name: "wide", Width: 1200, Height: 630}
+)
+
+// CardSizes lists every card size.
+var CardSizes = []CardSize{CardStory, CardWide}
+
+func (c CardSize) String() string {
+	return fmt.Sprintf("%dx%d", c.Width, c.Height)
+}
+
+// ParseCardSize accepts a size name ("story", "wide") or "WIDTHxHEIGHT".
+func ParseCardSize(v string) (CardSize, error) {
+	for _, c := range CardSizes {
+		if v == c.Name || v == c.String() {
+			return c, nil
+		}
+	}
+	return CardSize{}, fmt.Errorf("invalid card size %q: must be story (%s) or wide (%s)", v, CardStory, CardWide)
+}
+
+var (
+	cardBackground = color.RGBA{0x14, 0x14, 0x14, 0xff}
+	cardAccent     = color.RGBA{0xe5, 0x09, 0x14, 0xff}
+	cardText       = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	cardMuted      = color.RGBA{0xb3, 0xb3, 0xb3, 0xff}
+	cardBarIdle    = color.RGBA{0x33, 0x33, 0x33, 0xff}
+)
+
+// cardFact is one labelled value on a card.
+type cardFact struct {
+	Label string
+	Value string
+}
+
+// cardContent is what a card shows, formatted in the report language.
+type cardContent struct {
+	Period     string
+	HoursLabel string
+	Hours      string
+	HoursUnit  string
+	Facts      []cardFact // top genre, top series, longest streak
+	ChartLabel string
+	Months     []chartBar
+	Footer     string
+}
+
+func newCardContent(s Stats) cardContent {
+	p := s.printer()
+	c := cardContent{
+		Period:     periodLabel(p, s.Period),
+		HoursLabel: p.T("card.total_hours"),
+		Hours:      p.Hours(s.TotalDurationMin),
+		HoursUnit:  p.T("card.hours_unit"),
+		ChartLabel: p.T("card.monthly"),
+		Footer:     p.T("card.footer", p.Int(s.TotalViews), s.ActiveDays),
+	}
+
+	topGenre, topSeries, streak := "-", "-", "-"
+	if len(s.GenreStats) > 0 {
+		topGenre = s.GenreStats[0].Name
+	}
+	series := s.TopSeriesByDuration
+	if s.Config.SortBy == SortByViews {
+		series = s.TopSeriesByViews
+	}
+	if len(series) > 0 {
+		topSeries = series[0].SeriesName
+	}
+	if len(s.TopStreaks) > 0 {
+		streak = p.T("days", s.TopStreaks[0].Days)
+	}
+	c.Facts = []cardFact{
+		{p.T("card.top_genre"), topGenre},
+		{p.T("card.top_series"), topSeries},
+		{p.T("card.longest_streak"), streak},
+	}
+
+	singleYear := s.Period.From.Year() == s.Period.To.Year()
+	for _, m := range s.MonthlySeries {
+		label := p.Month(m.Month)
+		if !singleYear && m.Month == 1 {
+			label = fmt.Sprint(m.Year)
+		}
+		c.Months = append(c.Months, chartBar{Label: label, Value: float64(m.DurationMin)})
+	}
+	return c
+}
+
+// RenderCardPNG draws a shareable summary card of s: total hours, top genre,
+// top series, longest streak and a monthly chart. It needs no network access
+// or browser; text uses the bundled font.
+func RenderCardPNG(s Stats, size CardSize) ([]byte, error) {
+	f, err := loadCardFont()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load card font: %w", err)
+	}
+	cv := &canvas{
+		img:   image.NewRGBA(image.Rect(0, 0, size.Width, size.Height)),
+		font:  f,
+		faces: make(map[float64]font.Face),
+	}
+	defer cv.close()
+	draw.Draw(cv.img, cv.img.Bounds(), image.NewUniform(cardBackground), image.Point{}, draw.Src)
+
+	c := newCardContent(s)
+	if size.Height > size.Width {
+		cv.drawPortrait(c)
+	} else {
+		cv.drawLandscape(c)
+	}
+	if cv.err != nil {
+		return nil, cv.err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, cv.img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteCardPNGs writes every card size of s into dir as card-WxH.png.
+// The directory is created if needed.
+func WriteCardPNGs(s Stats, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, size := range CardSizes {
+		b, err := RenderCardPNG(s, size)
+		if err != nil {
+			return err
+		}
+		name := "card-" + size.String() + ".png"
+		if err := os.WriteFile(filepath.Join(dir, name), b, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// canvas draws text and boxes onto a card. Drawing stops at the first
+// error, which is kept in err.
+type canvas struct {
+	img   *image.RGBA
+	font  *opentype.Font
+	faces map[float64]font.Face // by size in px
+	err   error
+}
+
+func (cv *canvas) close() {
+	for _, f := range cv.faces {
+		f.Close()
+	}
+}
+
+func (cv *canvas) face(size float64) font.Face {
+	if f, ok := cv.faces[size]; ok {
+		return f
+	}
+	f, err := opentype.NewFace(cv.font, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		cv.err = err
+		return nil
+	}
+	cv.faces[size] = f
+	return f
+}
+
+// text draws s with its baseline at y, cut with "…" to fit maxWidth.
+// It returns the x where the text ends.
+func (cv *canvas) text(x, y int, size float64, col color.Color, s string, maxWidth int) int {
+	if cv.err != nil {
+		return x
+	}
+	face := cv.face(size)
+	if face == nil {
+		return x
+	}
+	d := &font.Drawer{Dst: cv.img, Src: image.NewUniform(col), Face: face}
+	d.Dot = fixed.P(x, y)
+	d.DrawString(fitText(d, s, fixed.I(maxWidth)))
+	return d.Dot.X.Round()
+}
+
+// total draws the total hours large, followed by the unit at a third of the
+// size, both within maxWidth.
+func (cv *canvas) total(x, y int, size float64, c cardContent, maxWidth int) {
+	end := cv.text(x, y, size, cardAccent, c.Hours, maxWidth)
+	gap := int(size * 0.15)
+	cv.text(end+gap, y, size/3, cardAccent, c.HoursUnit, x+maxWidth-end-gap)
+}
+
+func fitText(d *font.Drawer, s string, maxWidth fixed.Int26_6) string {
+	if d.MeasureString(s) <= maxWidth {
+		return s
+	}
+	r := []rune(s)
+	for len(r) > 0 && d.MeasureString(string(r)+"…") > maxWidth {
+		r = r[:len(r)-1]
+	}
+	return strings.TrimSpace(string(r)) + "…"
+}
+
+func (cv *canvas) fill(r image.Rectangle, col color.Color) {
+	draw.Draw(cv.img, r, image.NewUniform(col), image.Point{}, draw.Src)
+}
+
+// facts draws label/value pairs from y downwards, step px apart.
+func (cv *canvas) facts(x, y, step, width int, labelSize, valueSize float64, facts []cardFact) {
+	for i, f := range facts {
+		top := y + i*step
+		cv.text(x, top, labelSize, cardMuted, f.Label, width)
+		cv.text(x, top+int(valueSize*1.25), valueSize, cardText, f.Value, width)
+	}
+}
+
+// chart draws a bar per month into r, with labels below r.
+func (cv *canvas) chart(r image.Rectangle, labelSize float64, bars []chartBar) {
+	face := cv.face(labelSize)
+	if len(bars) == 0 || face == nil {
+		return
+	}
+	maxV := 0.0
+	labelW := 0
+	for _, b := range bars {
+		maxV = max(maxV, b.Value)
+		labelW = max(labelW, font.MeasureString(face, b.Label).Ceil())
+	}
+	slot := float64(r.Dx()) / float64(len(bars))
+	gap := slot * 0.2
+	// Label every n-th bar so that labels do not overlap.
+	every := 1
+	for float64(every)*slot < float64(labelW)*1.2 {
+		every++
+	}
+	for i, b := range bars {
+		x0 := r.Min.X + int(float64(i)*slot+gap/2)
+		x1 := r.Min.X + int(float64(i+1)*slot-gap/2)
+		h := 0
+		if maxV > 0 {
+			h = int(b.Value / maxV * float64(r.Dy()))
+		}
+		cv.fill(image.Rect(x0, r.Min.Y, x1, r.Max.Y), cardBarIdle)
+		cv.fill(image.Rect(x0, r.Max.Y-h, x1, r.Max.Y), cardAccent)
+		if i%every == 0 {
+			w := font.MeasureString(face, b.Label).Round()
+			cv.text((x0+x1-w)/2, r.Max.Y+int(labelSize*1.4), labelSize, cardMuted, b.Label, labelW)
+		}
+	}
+}
+
+// drawPortrait lays out a 9:16 card, e.g. CardStory. Positions are given for
+// 1080px wide and scaled.
+func (cv *canvas) drawPortrait(c cardContent) {
+	w, h := cv.img.Bounds().Dx(), cv.img.Bounds().Dy()
+	u := float64(w) / 1080
+	px := func(v float64) int { return int(v * u) }
+	margin := px(80)
+	inner := w - 2*margin
+
+	cv.fill(image.Rect(margin, px(120), margin+px(120), px(132)), cardAccent)
+	cv.text(margin, px(215), 56*u, cardText, "Netflix Recap", inner)
+	cv.text(margin, px(290), 48*u, cardText, c.Period, inner)
+	cv.text(margin, px(400), 40*u, cardMuted, c.HoursLabel, inner)
+	cv.total(margin, px(580), 180*u, c, inner)
+	cv.facts(margin, px(720), px(170), inner, 36*u, 64*u, c.Facts)
+	cv.text(margin, px(1290), 36*u, cardMuted, c.ChartLabel, inner)
+	cv.chart(image.Rect(margin, px(1340), w-margin, px(1680)), 28*u, c.Months)
+	cv.text(margin, h-px(100), 32*u, cardMuted, c.Footer, inner)
+}
+
+// drawLandscape lays out a wide card, e.g. CardWide: the totals on the left,
+// the facts on the right and the chart along the bottom. Positions are given
+// for 630px high and scaled.
+func (cv *canvas) drawLandscape(c cardContent) {
+	w := cv.img.Bounds().Dx()
+	u := float64(cv.img.Bounds().Dy()) / 630
+	px := func(v float64) int { return int(v * u) }
+	margin := px(60)
+	col := (w - 3*margin) / 2
+
+	cv.fill(image.Rect(margin, px(40), margin+px(80), px(48)), cardAccent)
+	cv.text(margin, px(110), 40*u, cardText, "Netflix Recap "+c.Period, w-2*margin)
+	cv.text(margin, px(190), 28*u, cardMuted, c.HoursLabel, col)
+	cv.total(margin, px(300), 120*u, c, col)
+	cv.text(margin, px(360), 24*u, cardMuted, c.Footer, col)
+	cv.facts(2*margin+col, px(170), px(88), col, 22*u, 34*u, c.Facts)
+	cv.chart(image.Rect(margin, px(420), w-margin, px(560)), 20*u, c.Months)
+}
@@ -0,0 +1,97 @@
+package recap
+
+import (
+	"bytes"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+func TestParseCardSize(t *testing.T) {
+	for _, v := range []string{"story", "1080x1920"} {
+		c, err := ParseCardSize(v)
+		require.NoError(t, err, v)
+		assert.Equal(t, CardStory, c)
+	}
+	c, err := ParseCardSize("wide")
+	require.NoError(t, err)
+	assert.Equal(t, CardWide, c)
+
+	_, err = ParseCardSize("800x600")
+	assert.ErrorContains(t, err, "invalid card size")
+}
+
+func TestNewCardContent(t *testing.T) {
+	c := newCardContent(exportTestStats())
+
+	assert.Equal(t, "2024", c.Period)
+	assert.Equal(t, "3.0", c.Hours)
+	assert.Equal(t, []cardFact{
+		{"いちばん観たジャンル", "ドラマ"},
+		{"いちばん観たシリーズ", "Show"},
+		{"最長連続視聴", "3 日"},
+	}, c.Facts)
+	require.Len(t, c.Months, 12)
+	assert.Equal(t, 180.0, c.Months[0].Value)
+	assert.Equal(t, "4 本視聴 / 視聴日数 4 日", c.Footer)
+
+	empty := newCardContent(ComputeStats(build.Built{}, 2024))
+	for _, f := range empty.Facts {
+		assert.Equal(t, "-", f.Value, f.Label)
+	}
+}
+
+func TestNewCardContentEnglish(t *testing.T) {
+	s := exportTestStats()
+	s.Config.Lang = "en"
+	c := newCardContent(s)
+
+	assert.Equal(t, "Estimated watch time", c.HoursLabel)
+	assert.Equal(t, "Jan", c.Months[0].Label)
+	assert.Equal(t, cardFact{"Longest streak", "3 days"}, c.Facts[2])
+}
+
+func TestRenderCardPNG(t *testing.T) {
+	for _, s := range []Stats{exportTestStats(), ComputeStats(build.Built{}, 2024)} {
+		for _, size := range CardSizes {
+			b, err := RenderCardPNG(s, size)
+			require.NoError(t, err, size.Name)
+			cfg, err := png.DecodeConfig(bytes.NewReader(b))
+			require.NoError(t, err, size.Name)
+			assert.Equal(t, size.Width, cfg.Width, size.Name)
+			assert.Equal(t, size.Height, cfg.Height, size.Name)
+		}
+	}
+}
+
+func TestWriteCardPNGs(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cards")
+	require.NoError(t, WriteCardPNGs(exportTestStats(), dir))
+
+	for _, name := range []string{"card-1080x1920.png", "card-1200x630.png"} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		assert.NoError(t, err, name)
+	}
+}
+
+func TestFitText(t *testing.T) {
+	f, err := loadCardFont()
+	require.NoError(t, err)
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: 20, DPI: 72})
+	require.NoError(t, err)
+	defer face.Close()
+	d := &font.Drawer{Face: face}
+
+	assert.Equal(t, "短い", fitText(d, "短い", fixed.I(1000)))
+	got := fitText(d, "とても長いシリーズのタイトル", fixed.I(100))
+	assert.Equal(t, "…", got[len(got)-len("…"):])
+	assert.LessOrEqual(t, d.MeasureString(got), fixed.I(100))
+}
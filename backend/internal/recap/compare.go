@@ -0,0 +1,132 @@
+package recap
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// topGenreCount is how many leading genres count as "favorites" when
+// looking for newly favored genres.
+const topGenreCount = 5
+
+type StatsDiff struct {
+	BasePeriod Period
+
+	BaseTotalViews       int
+	BaseTotalDurationMin int
+	BaseActiveDays       int
+
+	TotalViewsDelta       int
+	TotalDurationMinDelta int
+	TotalDurationPct      float64 // change relative to base, 0 if base is empty
+	ActiveDaysDelta       int
+
+	GenreShifts       []GenreShift // sorted by absolute share change
+	NewTopGenres      []string     // in the current top genres but not in the base top genres
+	CarriedOverSeries []string     // series watched in both periods
+	MonthShifts       []MonthShift // sorted by absolute duration change; none if a period spans more than 12 months
+}
+
+type GenreShift struct {
+	Key       string
+	Name      string
+	BaseShare float64
+	Share     float64
+	DeltaPt   float64 // percentage points
+}
+
+type MonthShift struct {
+	Month           time.Month
+	BaseDurationMin int
+	DurationMin     int
+	DeltaMin        int
+}
+
+// Compare computes how cur changed relative to base.
+func Compare(cur, base Stats) StatsDiff {
+	d := StatsDiff{
+		BasePeriod:            base.Period,
+		BaseTotalViews:        base.TotalViews,
+		BaseTotalDurationMin:  base.TotalDurationMin,
+		BaseActiveDays:        base.ActiveDays,
+		TotalViewsDelta:       cur.TotalViews - base.TotalViews,
+		TotalDurationMinDelta: cur.TotalDurationMin - base.TotalDurationMin,
+		ActiveDaysDelta:       cur.ActiveDays - base.ActiveDays,
+	}
+	if base.TotalDurationMin > 0 {
+		d.TotalDurationPct = float64(d.TotalDurationMinDelta) / float64(base.TotalDurationMin) * 100
+	}
+
+	// Genre share shifts
+	shares := make(map[string]*GenreShift)
+	for _, g := range base.GenreStats {
+		shares[g.Key] = &GenreShift{Key: g.Key, Name: g.Name, BaseShare: g.Share}
+	}
+	for _, g := range cur.GenreStats {
+		if _, ok := shares[g.Key]; !ok {
+			shares[g.Key] = &GenreShift{Key: g.Key, Name: g.Name}
+		}
+		shares[g.Key].Share = g.Share
+	}
+	for _, gs := range shares {
+		gs.DeltaPt = gs.Share - gs.BaseShare
+		d.GenreShifts = append(d.GenreShifts, *gs)
+	}
+	sort.Slice(d.GenreShifts, func(i, j int) bool {
+		a, b := math.Abs(d.GenreShifts[i].DeltaPt), math.Abs(d.GenreShifts[j].DeltaPt)
+		if a != b {
+			return a > b
+		}
+		return d.GenreShifts[i].Key < d.GenreShifts[j].Key
+	})
+
+	// New favorite genres
+	baseTop := make(map[string]bool)
+	for i, g := range base.GenreStats {
+		if i >= topGenreCount {
+			break
+		}
+		baseTop[g.Key] = true
+	}
+	for i, g := range cur.GenreStats {
+		if i >= topGenreCount {
+			break
+		}
+		if !baseTop[g.Key] {
+			d.NewTopGenres = append(d.NewTopGenres, g.Name)
+		}
+	}
+
+	// Series carried over, from every series, not only the top ones
+	baseSeries := make(map[string]bool)
+	for _, ser := range base.Series {
+		baseSeries[ser.SeriesName] = true
+	}
+	for _, ser := range cur.Series {
+		if baseSeries[ser.SeriesName] {
+			d.CarriedOverSeries = append(d.CarriedOverSeries, ser.SeriesName)
+		}
+	}
+
+	// Month shifts, unless a period is too long for MonthlyStats
+	for m := time.January; m <= time.December && len(cur.MonthlySeries) <= 12 && len(base.MonthlySeries) <= 12; m++ {
+		b, c := base.MonthlyStats[m].DurationMin, cur.MonthlyStats[m].DurationMin
+		if b == 0 && c == 0 {
+			continue
+		}
+		d.MonthShifts = append(d.MonthShifts, MonthShift{Month: m, BaseDurationMin: b, DurationMin: c, DeltaMin: c - b})
+	}
+	sort.SliceStable(d.MonthShifts, func(i, j int) bool {
+		return absInt(d.MonthShifts[i].DeltaMin) > absInt(d.MonthShifts[j].DeltaMin)
+	})
+
+	return d
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,106 @@
+package recap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	item := func(date, title, typ string, runtime int, genres ...string) build.BuiltItem {
+		return build.BuiltItem{
+			Date:       date,
+			Normalized: model.NormalizedTitle{WorkTitle: title, Type: typ},
+			Metadata:   &model.Metadata{Runtime: runtime, Genres: genres},
+		}
+	}
+	built := build.Built{Items: []build.BuiltItem{
+		item("2023-01-10", "Long Show", "tv", 60, "Drama"),
+		item("2023-01-11", "Old Movie", "movie", 60, "Action"),
+		item("2024-01-10", "Long Show", "tv", 60, "Drama"),
+		item("2024-06-01", "New Movie", "movie", 120, "Animation"),
+		item("2024-06-02", "New Show", "tv", 60, "Animation"),
+	}}
+
+	cur := ComputeStats(built, 2024)
+	base := ComputeStats(built, 2023)
+	d := Compare(cur, base)
+
+	assert.Equal(t, 2023, d.BasePeriod.From.Year())
+	assert.Equal(t, 1, d.TotalViewsDelta)
+	assert.Equal(t, 120, d.TotalDurationMinDelta)
+	assert.InDelta(t, 100.0, d.TotalDurationPct, 0.01)
+	assert.Equal(t, 1, d.ActiveDaysDelta)
+
+	if assert.NotEmpty(t, d.GenreShifts) {
+		// Animation: 0% -> 75%
+		assert.Equal(t, "animation", d.GenreShifts[0].Key)
+		assert.Equal(t, "アニメーション", d.GenreShifts[0].Name)
+		assert.InDelta(t, 75.0, d.GenreShifts[0].DeltaPt, 0.01)
+	}
+	assert.Equal(t, []string{"アニメーション"}, d.NewTopGenres)
+	assert.Equal(t, []string{"Long Show"}, d.CarriedOverSeries)
+
+	if assert.Len(t, d.MonthShifts, 2) {
+		assert.Equal(t, time.June, d.MonthShifts[0].Month)
+		assert.Equal(t, 180, d.MonthShifts[0].DeltaMin)
+		assert.Equal(t, time.January, d.MonthShifts[1].Month)
+		assert.Equal(t, -60, d.MonthShifts[1].DeltaMin)
+	}
+
+	cur.Comparison = &d
+	md := RenderMarkdown(cur)
+	assert.Contains(t, md, "## 比較：2023 → 2024")
+	assert.Contains(t, md, "| アニメーション | 0.0% | 75.0% | +75.0 |")
+}
+
+func TestCompareLongPeriod(t *testing.T) {
+	item := func(date string) build.BuiltItem {
+		return build.BuiltItem{
+			Date:       date,
+			Normalized: model.NormalizedTitle{WorkTitle: "Movie", Type: "movie"},
+			Metadata:   &model.Metadata{Runtime: 60},
+		}
+	}
+	built := build.Built{Items: []build.BuiltItem{item("2023-03-20"), item("2024-03-10"), item("2022-03-01")}}
+
+	// 13 calendar months: March occurs twice and must not be merged
+	p, err := ParsePeriod("2023-03-15", "2024-03-14")
+	assert.NoError(t, err)
+	cur := ComputeStatsWithOptions(built, Options{Period: p})
+	assert.Empty(t, cur.MonthlyStats)
+	assert.Len(t, cur.MonthlySeries, 13)
+
+	d := Compare(cur, ComputeStats(built, 2022))
+	assert.Empty(t, d.MonthShifts)
+	assert.Equal(t, 1, d.TotalViewsDelta)
+}
+
+func TestCompareCarriedOverBeyondLimit(t *testing.T) {
+	item := func(date, title string, runtime int) build.BuiltItem {
+		return build.BuiltItem{
+			Date:       date,
+			Normalized: model.NormalizedTitle{WorkTitle: title, Type: "tv"},
+			Metadata:   &model.Metadata{Runtime: runtime},
+		}
+	}
+	built := build.Built{Items: []build.BuiltItem{
+		item("2023-02-01", "Big Show", 300),
+		item("2023-02-02", "Small Show", 30),
+		item("2024-02-01", "Other Show", 300),
+		item("2024-02-02", "Small Show", 30),
+	}}
+
+	opts := func(year int) Options {
+		return Options{Period: YearPeriod(year), Config: Config{Limits: Limits{Titles: 1}}}
+	}
+	cur := ComputeStatsWithOptions(built, opts(2024))
+	base := ComputeStatsWithOptions(built, opts(2023))
+	assert.Len(t, cur.TopSeriesByDuration, 1)
+
+	d := Compare(cur, base)
+	assert.Equal(t, []string{"Small Show"}, d.CarriedOverSeries)
+}
@@ -0,0 +1,142 @@
+package recap
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+)
+
+// abandonAfterDays is how long an unfinished series may stay unwatched
+// until it counts as abandoned; see asOf.
+const abandonAfterDays = 60
+
+// asOf is the date that abandonAfterDays counts up to: the end of the
+// period, or the last view of the history if that is earlier. A recap of the
+// current year thus measures from the export, not from December 31.
+func asOf(items []build.BuiltItem, to time.Time) time.Time {
+	var last time.Time
+	for _, it := range items {
+		d, err := time.Parse("2006-01-02", it.Date)
+		if err == nil && d.After(last) {
+			last = d
+		}
+	}
+	if last.IsZero() || last.After(to) {
+		return to
+	}
+	return last
+}
+
+const (
+	SeriesStatusFinished   = "finished"
+	SeriesStatusAbandoned  = "abandoned"
+	SeriesStatusInProgress = "in_progress"
+)
+
+type SeriesProgress struct {
+	SeriesName      string
+	WatchedEpisodes int
+	TotalEpisodes   int
+	CompletionPct   float64
+	Status          string // "finished" | "abandoned" | "in_progress"
+	LastWatched     time.Time
+	CompletedAt     time.Time // zero unless finished
+}
+
+type episodeView struct {
+	date time.Time
+	key  string
+}
+
+// computeSeriesCompletion looks at the whole history up to `to`, so a series
+// started in an earlier period and finished in this one is reported as finished.
+// Only series watched within [from, to] with known episode counts are listed.
+// Unfinished series not watched since abandonAfterDays before now are abandoned.
+func (s *Stats) computeSeriesCompletion(items []build.BuiltItem, from, to, now time.Time) {
+	views := make(map[string][]episodeView) // SeriesName -> views
+	totals := make(map[string]int)          // SeriesName -> total episodes
+	inPeriod := make(map[string]bool)
+
+	for _, it := range items {
+		if it.Normalized.Type != "tv" {
+			continue
+		}
+		d, err := time.Parse("2006-01-02", it.Date)
+		if err != nil || d.After(to) {
+			continue
+		}
+		sn := it.Normalized.WorkTitle
+		views[sn] = append(views[sn], episodeView{date: d, key: episodeKey(it)})
+		if !d.Before(from) {
+			inPeriod[sn] = true
+		}
+		if it.Metadata != nil {
+			if total := it.Metadata.TotalEpisodes(); total > totals[sn] {
+				totals[sn] = total
+			}
+		}
+	}
+
+	for sn, vs := range views {
+		total := totals[sn]
+		if !inPeriod[sn] || total == 0 {
+			continue
+		}
+		sort.Slice(vs, func(i, j int) bool { return vs[i].date.Before(vs[j].date) })
+
+		p := SeriesProgress{SeriesName: sn, TotalEpisodes: total}
+		seen := make(map[string]bool)
+		for _, v := range vs {
+			if !seen[v.key] {
+				seen[v.key] = true
+				if len(seen) == total {
+					p.CompletedAt = v.date
+				}
+			}
+			p.LastWatched = v.date
+		}
+		p.WatchedEpisodes = min(len(seen), total)
+		p.CompletionPct = float64(p.WatchedEpisodes) / float64(total) * 100
+
+		switch {
+		case !p.CompletedAt.IsZero():
+			if p.CompletedAt.Before(from) {
+				// finished in an earlier period, only rewatched now
+				continue
+			}
+			p.Status = SeriesStatusFinished
+			s.SeriesFinished = append(s.SeriesFinished, p)
+		case now.Sub(p.LastWatched) >= abandonAfterDays*24*time.Hour:
+			p.Status = SeriesStatusAbandoned
+			s.SeriesAbandoned = append(s.SeriesAbandoned, p)
+		default:
+			p.Status = SeriesStatusInProgress
+			s.SeriesInProgress = append(s.SeriesInProgress, p)
+		}
+	}
+
+	sort.Slice(s.SeriesFinished, func(i, j int) bool {
+		return s.SeriesFinished[i].CompletedAt.Before(s.SeriesFinished[j].CompletedAt)
+	})
+	sort.Slice(s.SeriesAbandoned, func(i, j int) bool {
+		return s.SeriesAbandoned[i].WatchedEpisodes > s.SeriesAbandoned[j].WatchedEpisodes
+	})
+	sort.Slice(s.SeriesInProgress, func(i, j int) bool {
+		return s.SeriesInProgress[i].CompletionPct > s.SeriesInProgress[j].CompletionPct
+	})
+}
+
+// episodeKey identifies an episode within its series. Structured numbers are
+// preferred; otherwise the season label and episode title are used as-is.
+func episodeKey(it build.BuiltItem) string {
+	n := it.Normalized
+	if n.SeasonNumber > 0 && n.EpisodeNumber > 0 {
+		return fmt.Sprintf("s%d:e%d", n.SeasonNumber, n.EpisodeNumber)
+	}
+	if n.Season == "" && n.EpisodeTitle == "" {
+		return n.RawTitle
+	}
+	return n.Season + "|" + n.EpisodeTitle
+}
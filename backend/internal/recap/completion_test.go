@@ -0,0 +1,116 @@
+package recap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeSeriesCompletion(t *testing.T) {
+	twoSeasons := &model.Metadata{
+		Runtime: 30,
+		Seasons: []model.Season{{Number: 1, EpisodeCount: 2}, {Number: 2, EpisodeCount: 1}},
+	}
+	ep := func(date, series string, season, episode int, md *model.Metadata) build.BuiltItem {
+		return build.BuiltItem{
+			Date: date,
+			Normalized: model.NormalizedTitle{
+				WorkTitle:     series,
+				Type:          "tv",
+				SeasonNumber:  season,
+				EpisodeNumber: episode,
+			},
+			Metadata: md,
+		}
+	}
+
+	items := []build.BuiltItem{
+		// Finished: started last year, last episode this year
+		ep("2022-12-30", "Done", 1, 1, twoSeasons),
+		ep("2023-02-01", "Done", 1, 2, twoSeasons),
+		ep("2023-02-01", "Done", 1, 2, twoSeasons), // same episode twice
+		ep("2023-02-03", "Done", 2, 1, twoSeasons),
+		// Abandoned: stopped in spring
+		ep("2023-03-01", "Dropped", 1, 1, twoSeasons),
+		// In progress: watched recently
+		ep("2023-12-20", "Ongoing", 1, 1, twoSeasons),
+		ep("2023-12-21", "Ongoing", 1, 2, twoSeasons),
+		// Unknown episode counts are not reported
+		ep("2023-05-01", "NoMeta", 1, 1, nil),
+		// Finished in an earlier year, only rewatched now
+		ep("2022-01-01", "Old", 1, 1, &model.Metadata{Seasons: []model.Season{{Number: 1, EpisodeCount: 1}}}),
+		ep("2023-01-01", "Old", 1, 1, &model.Metadata{Seasons: []model.Season{{Number: 1, EpisodeCount: 1}}}),
+	}
+
+	s := ComputeStats(build.Built{Items: items}, 2023)
+
+	if assert.Len(t, s.SeriesFinished, 1) {
+		f := s.SeriesFinished[0]
+		assert.Equal(t, "Done", f.SeriesName)
+		assert.Equal(t, 3, f.WatchedEpisodes)
+		assert.Equal(t, 3, f.TotalEpisodes)
+		assert.Equal(t, 100.0, f.CompletionPct)
+		assert.Equal(t, "2023-02-03", f.CompletedAt.Format("2006-01-02"))
+		assert.Equal(t, SeriesStatusFinished, f.Status)
+	}
+	if assert.Len(t, s.SeriesAbandoned, 1) {
+		a := s.SeriesAbandoned[0]
+		assert.Equal(t, "Dropped", a.SeriesName)
+		assert.Equal(t, 1, a.WatchedEpisodes)
+		assert.Equal(t, SeriesStatusAbandoned, a.Status)
+	}
+	if assert.Len(t, s.SeriesInProgress, 1) {
+		p := s.SeriesInProgress[0]
+		assert.Equal(t, "Ongoing", p.SeriesName)
+		assert.InDelta(t, 66.6, p.CompletionPct, 0.1)
+		assert.Equal(t, "2023-12-21", p.LastWatched.Format("2006-01-02"))
+	}
+}
+
+func TestComputeSeriesCompletionMidYear(t *testing.T) {
+	md := &model.Metadata{Seasons: []model.Season{{Number: 1, EpisodeCount: 3}}}
+	ep := func(date, series string, episode int) build.BuiltItem {
+		return build.BuiltItem{
+			Date:       date,
+			Normalized: model.NormalizedTitle{WorkTitle: series, Type: "tv", SeasonNumber: 1, EpisodeNumber: episode},
+			Metadata:   md,
+		}
+	}
+	// Exported in June: 60 days are counted back from the last view, not
+	// from December 31.
+	items := []build.BuiltItem{
+		ep("2023-03-01", "Dropped", 1),
+		ep("2023-05-01", "Paused", 1),
+		ep("2023-06-15", "Ongoing", 1),
+	}
+
+	s := ComputeStats(build.Built{Items: items}, 2023)
+
+	if assert.Len(t, s.SeriesAbandoned, 1) {
+		assert.Equal(t, "Dropped", s.SeriesAbandoned[0].SeriesName)
+	}
+	assert.Len(t, s.SeriesInProgress, 2)
+}
+
+func TestAsOf(t *testing.T) {
+	end := time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)
+	items := []build.BuiltItem{{Date: "2023-06-15"}, {Date: "2023-02-01"}, {Date: "bad"}}
+	assert.Equal(t, time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC), asOf(items, end))
+	assert.Equal(t, end, asOf(append(items, build.BuiltItem{Date: "2024-01-05"}), end))
+	assert.Equal(t, end, asOf(nil, end))
+}
+
+func TestEpisodeKey(t *testing.T) {
+	assert.Equal(t, "s1:e2", episodeKey(build.BuiltItem{
+		Normalized: model.NormalizedTitle{SeasonNumber: 1, EpisodeNumber: 2, EpisodeTitle: "ignored"},
+	}))
+	assert.Equal(t, "Limited|Pilot", episodeKey(build.BuiltItem{
+		Normalized: model.NormalizedTitle{Season: "Limited", EpisodeTitle: "Pilot"},
+	}))
+	assert.Equal(t, "Show: Special", episodeKey(build.BuiltItem{
+		Normalized: model.NormalizedTitle{RawTitle: "Show: Special"},
+	}))
+}
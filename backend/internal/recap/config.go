@@ -0,0 +1,259 @@
+package recap
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/kmdkuk/nfrecap/internal/i18n"
+)
+
+// Sort keys for rankings.
+const (
+	SortByDuration = "duration"
+	SortByViews    = "views"
+)
+
+// Report sections that can be turned off.
+const (
+	SectionComparison   = "comparison"
+	SectionMonthly      = "monthly"
+	SectionWeekday      = "weekday"
+	SectionWeekly       = "weekly"
+	SectionHolidays     = "holidays"
+	SectionTimeOfDay    = "time_of_day"
+	SectionDevices      = "devices"
+	SectionStreaks      = "streaks"
+	SectionCalendar     = "calendar"
+	SectionGenres       = "genres"
+	SectionGenreTrends  = "genre_trends"
+	SectionGenreSamples = "genre_samples"
+	SectionTitles       = "titles"
+	SectionSeries       = "series"
+	SectionCompletion   = "completion"
+	SectionRewatches    = "rewatches"
+	SectionHabits       = "habits"
+	SectionDataQuality  = "data_quality"
+
+	// SectionUnresolvedAppendix lists every unresolved work with its raw
+	// titles. Off unless enabled explicitly.
+	SectionUnresolvedAppendix = "unresolved_appendix"
+)
+
+// Sections lists every section in report order.
+var Sections = []string{
+	SectionComparison,
+	SectionMonthly,
+	SectionWeekday,
+	SectionWeekly,
+	SectionHolidays,
+	SectionTimeOfDay,
+	SectionDevices,
+	SectionStreaks,
+	SectionCalendar,
+	SectionGenres,
+	SectionGenreTrends,
+	SectionGenreSamples,
+	SectionTitles,
+	SectionSeries,
+	SectionCompletion,
+	SectionRewatches,
+	SectionHabits,
+	SectionDataQuality,
+	SectionUnresolvedAppendix,
+}
+
+// optInSections are off unless enabled in Config.Sections.
+var optInSections = map[string]bool{
+	SectionUnresolvedAppendix: true,
+}
+
+// Limits controls how many entries the rankings keep in Stats and show in
+// the report. Zero fields fall back to DefaultLimits.
+type Limits struct {
+	Streaks           int `mapstructure:"streaks" json:"streaks"`
+	Genres            int `mapstructure:"genres" json:"genres"`                           // genres listed before "other"
+	GenreSamples      int `mapstructure:"genre_samples" json:"genre_samples"`             // movies kept per genre
+	GenreSamplesShown int `mapstructure:"genre_samples_shown" json:"genre_samples_shown"` // movies shown per genre
+	Titles            int `mapstructure:"titles" json:"titles"`                           // entries kept per title, series and rewatch ranking
+	TitlesShown       int `mapstructure:"titles_shown" json:"titles_shown"`               // rows shown per ranking
+	Unresolved        int `mapstructure:"unresolved" json:"unresolved"`
+}
+
+// Set sets the limit named by its config key, e.g. "titles_shown". The
+// limit must be positive, as zero stands for the default.
+func (l *Limits) Set(name string, v int) error {
+	fields := map[string]*int{
+		"streaks":             &l.Streaks,
+		"genres":              &l.Genres,
+		"genre_samples":       &l.GenreSamples,
+		"genre_samples_shown": &l.GenreSamplesShown,
+		"titles":              &l.Titles,
+		"titles_shown":        &l.TitlesShown,
+		"unresolved":          &l.Unresolved,
+	}
+	f, ok := fields[name]
+	if !ok {
+		return fmt.Errorf("unknown limit %q", name)
+	}
+	if v < 1 {
+		return fmt.Errorf("limit %q must be positive", name)
+	}
+	*f = v
+	return nil
+}
+
+var DefaultLimits = Limits{
+	Streaks:           3,
+	Genres:            10,
+	GenreSamples:      5,
+	GenreSamplesShown: 3,
+	Titles:            50,
+	TitlesShown:       10,
+	Unresolved:        30,
+}
+
+// Config holds the user-facing report settings. The zero value means the
+// defaults: DefaultLimits, rankings by duration, every section but the
+// opt-in ones enabled and reports in i18n.DefaultLang.
+type Config struct {
+	Limits   Limits          `mapstructure:"limits" json:"limits"`
+	SortBy   string          `mapstructure:"sort_by" json:"sort_by"`
+	Sections map[string]bool `mapstructure:"sections" json:"sections,omitempty"` // section -> enabled, missing sections use their default
+	Lang     string          `mapstructure:"lang" json:"lang"`                   // report and genre name language, e.g. "en"
+}
+
+// withDefaults fills zero fields with the defaults.
+func (c Config) withDefaults() Config {
+	fill := func(v *int, def int) {
+		if *v <= 0 {
+			*v = def
+		}
+	}
+	l := &c.Limits
+	fill(&l.Streaks, DefaultLimits.Streaks)
+	fill(&l.Genres, DefaultLimits.Genres)
+	fill(&l.GenreSamples, DefaultLimits.GenreSamples)
+	fill(&l.GenreSamplesShown, DefaultLimits.GenreSamplesShown)
+	fill(&l.Titles, DefaultLimits.Titles)
+	fill(&l.TitlesShown, DefaultLimits.TitlesShown)
+	fill(&l.Unresolved, DefaultLimits.Unresolved)
+	if c.SortBy == "" {
+		c.SortBy = SortByDuration
+	}
+	if c.Lang == "" {
+		c.Lang = i18n.DefaultLang
+	}
+	return c
+}
+
+// Validate checks the sort key, section names, limits and language.
+func (c Config) Validate() error {
+	if c.SortBy != "" && c.SortBy != SortByDuration && c.SortBy != SortByViews {
+		return fmt.Errorf("invalid sort key %q: must be %q or %q", c.SortBy, SortByDuration, SortByViews)
+	}
+	if c.Lang != "" {
+		if _, err := i18n.New(c.Lang); err != nil {
+			return err
+		}
+	}
+	for name := range c.Sections {
+		if !slices.Contains(Sections, name) {
+			return fmt.Errorf("unknown section %q", name)
+		}
+	}
+	l := c.Limits
+	for _, v := range []int{l.Streaks, l.Genres, l.GenreSamples, l.GenreSamplesShown, l.Titles, l.TitlesShown, l.Unresolved} {
+		if v < 0 {
+			return fmt.Errorf("limits must not be negative")
+		}
+	}
+	return nil
+}
+
+// Enabled reports whether section is shown.
+func (c Config) Enabled(section string) bool {
+	if enabled, ok := c.Sections[section]; ok {
+		return enabled
+	}
+	return !optInSections[section]
+}
+
+// WithoutDisabledSections returns a copy of s without the data of disabled
+// sections, for APIs that return Stats as is.
+func (s Stats) WithoutDisabledSections() Stats {
+	c := s.Config
+	if !c.Enabled(SectionComparison) {
+		s.Comparison = nil
+	}
+	if !c.Enabled(SectionMonthly) {
+		s.MonthlyStats = nil
+		s.MonthlySeries = nil
+	}
+	if !c.Enabled(SectionWeekday) {
+		s.WeekdayStats = nil
+	}
+	if !c.Enabled(SectionWeekly) {
+		s.WeeklyStats = nil
+		s.BusiestWeek = WeekMetric{}
+		s.DayTypeStats = DayTypeStats{}
+	}
+	if !c.Enabled(SectionHolidays) {
+		s.HolidayStats = nil
+	}
+	if !c.Enabled(SectionTimeOfDay) {
+		s.TimedViews = 0
+		s.HourWeekdayHeatmap = [7][24]Metric{}
+		s.LateNightViews = 0
+		s.LateNightShare = 0
+		s.SessionCount = 0
+		s.AvgSessionMin = 0
+		s.LongestSession = Session{}
+	}
+	if !c.Enabled(SectionDevices) {
+		s.DeviceStats = nil
+	}
+	if !c.Enabled(SectionStreaks) {
+		s.TopStreaks = nil
+		s.MaxGap = Gap{}
+	}
+	if !c.Enabled(SectionCalendar) {
+		s.Daily = nil
+	}
+	if !c.Enabled(SectionGenres) {
+		s.GenreStats = nil
+		s.GenreMonthSpike = nil
+	}
+	if !c.Enabled(SectionGenreTrends) {
+		s.GenreTrends = nil
+	}
+	if !c.Enabled(SectionGenreSamples) {
+		s.GenreSampleMovies = nil
+	}
+	if !c.Enabled(SectionTitles) {
+		s.TopTitlesByDuration = nil
+		s.TopTitlesByViews = nil
+		s.Titles = nil
+	}
+	if !c.Enabled(SectionSeries) {
+		s.TopSeriesByDuration = nil
+		s.TopSeriesByViews = nil
+		s.Series = nil
+	}
+	if !c.Enabled(SectionCompletion) {
+		s.SeriesFinished = nil
+		s.SeriesAbandoned = nil
+		s.SeriesInProgress = nil
+	}
+	if !c.Enabled(SectionRewatches) {
+		s.FirstTimeViews = 0
+		s.RepeatViews = 0
+		s.TopRewatches = nil
+	}
+	if !c.Enabled(SectionHabits) {
+		s.Habits = HabitStats{}
+	}
+	if !c.Enabled(SectionDataQuality) && !c.Enabled(SectionUnresolvedAppendix) {
+		s.UnresolvedList = nil
+	}
+	return s
+}
@@ -0,0 +1,77 @@
+package recap
+
+import (
+	"testing"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.NoError(t, Config{SortBy: SortByViews, Sections: map[string]bool{SectionCalendar: false}}.Validate())
+	assert.ErrorContains(t, Config{SortBy: "rating"}.Validate(), "invalid sort key")
+	assert.ErrorContains(t, Config{Sections: map[string]bool{"nope": false}}.Validate(), `unknown section "nope"`)
+	assert.Error(t, Config{Limits: Limits{Titles: -1}}.Validate())
+	assert.NoError(t, Config{Lang: "en"}.Validate())
+	assert.ErrorContains(t, Config{Lang: "fr"}.Validate(), `unsupported language "fr"`)
+
+	var l Limits
+	require.NoError(t, l.Set("titles_shown", 20))
+	assert.Equal(t, 20, l.TitlesShown)
+	assert.ErrorContains(t, l.Set("bogus", 1), `unknown limit "bogus"`)
+	assert.Error(t, l.Set("genres", -1))
+	assert.ErrorContains(t, l.Set("streaks", 0), `limit "streaks" must be positive`)
+	assert.Zero(t, l.Streaks)
+
+	c := Config{Limits: Limits{Genres: 4}}.withDefaults()
+	assert.Equal(t, 4, c.Limits.Genres)
+	assert.Equal(t, DefaultLimits.Titles, c.Limits.Titles)
+	assert.Equal(t, SortByDuration, c.SortBy)
+	assert.Equal(t, "ja", c.Lang)
+	assert.True(t, c.Enabled(SectionTitles))
+}
+
+func TestComputeStatsWithConfig(t *testing.T) {
+	item := func(date, title string, runtime int, genres ...string) build.BuiltItem {
+		return build.BuiltItem{
+			Date:       date,
+			Normalized: model.NormalizedTitle{WorkTitle: title, Type: "movie"},
+			Metadata:   &model.Metadata{Runtime: runtime, Genres: genres},
+		}
+	}
+	items := []build.BuiltItem{
+		item("2024-01-01", "Long", 180, "Drama"),
+		item("2024-01-03", "Short", 20, "Comedy"),
+		item("2024-01-05", "Short", 20, "Comedy"),
+		item("2024-01-07", "Mid", 60, "Action"),
+	}
+	cfg := Config{
+		Limits:   Limits{Streaks: 2, Titles: 2, Genres: 1},
+		SortBy:   SortByViews,
+		Sections: map[string]bool{SectionCalendar: false, SectionHabits: false},
+	}
+	s := ComputeStatsWithOptions(build.Built{Items: items}, Options{Period: YearPeriod(2024), Config: cfg})
+
+	assert.Len(t, s.TopStreaks, 2)
+	assert.Len(t, s.TopTitlesByDuration, 2)
+	assert.Equal(t, "Short", s.TopTitlesByViews[0].Title)
+	require.NotEmpty(t, s.GenreStats)
+	assert.Equal(t, "drama", s.GenreStats[0].Key) // genres stay by watch time
+
+	md := RenderMarkdown(s)
+	assert.Contains(t, md, "## 3. ジャンル別分析（時間ベース）")
+	assert.Contains(t, md, "### ジャンル別 推定視聴時間（Top 1 + その他）")
+	assert.Contains(t, md, "### 視聴回数が多い作品（Top 2）")
+	assert.Contains(t, md, "| 1位 | Short | movie | 0.7 | 2 |")
+	assert.NotContains(t, md, "### 視聴カレンダー")
+	assert.NotContains(t, md, "### 視聴スタイル")
+	assert.Contains(t, md, "### 連続視聴ランキング（Top 2）")
+
+	api := s.WithoutDisabledSections()
+	assert.Nil(t, api.Daily)
+	assert.Zero(t, api.Habits.Works)
+	assert.NotEmpty(t, api.GenreStats)
+}
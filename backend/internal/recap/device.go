@@ -0,0 +1,151 @@
+package recap
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kmdkuk/nfrecap/internal/genre"
+)
+
+const (
+	DeviceTV      = "tv"
+	DevicePhone   = "phone"
+	DeviceTablet  = "tablet"
+	DeviceBrowser = "browser"
+	DeviceOther   = "other"   // device type not matched by any rule
+	DeviceUnknown = "unknown" // no device type recorded
+)
+
+// DeviceRule maps device types containing Pattern (case-insensitive) to Class.
+type DeviceRule struct {
+	Pattern string `mapstructure:"pattern" json:"pattern"`
+	Class   string `mapstructure:"class" json:"class"`
+}
+
+// DefaultDeviceRules classifies the "Device Type" values of the account
+// export. Rules are tried in order and the first match wins, so the more
+// specific patterns (e.g. "Android TV", "Chromecast") come first.
+var DefaultDeviceRules = []DeviceRule{
+	{Pattern: "Chromecast", Class: DeviceTV},
+	{Pattern: "Android TV", Class: DeviceTV},
+	{Pattern: "Google TV", Class: DeviceTV},
+	{Pattern: "Fire TV", Class: DeviceTV},
+	{Pattern: "Apple TV", Class: DeviceTV},
+	{Pattern: "TV", Class: DeviceTV},
+	{Pattern: "Roku", Class: DeviceTV},
+	{Pattern: "PS3", Class: DeviceTV},
+	{Pattern: "PS4", Class: DeviceTV},
+	{Pattern: "PS5", Class: DeviceTV},
+	{Pattern: "PlayStation", Class: DeviceTV},
+	{Pattern: "Xbox", Class: DeviceTV},
+	{Pattern: "Nintendo", Class: DeviceTV},
+	{Pattern: "Set Top", Class: DeviceTV},
+	{Pattern: "Streaming Stick", Class: DeviceTV},
+	{Pattern: "iPad", Class: DeviceTablet},
+	{Pattern: "Tablet", Class: DeviceTablet},
+	{Pattern: "Kindle", Class: DeviceTablet},
+	{Pattern: "iPhone", Class: DevicePhone},
+	{Pattern: "Phone", Class: DevicePhone},
+	{Pattern: "Android", Class: DevicePhone},
+	{Pattern: "Chrome", Class: DeviceBrowser},
+	{Pattern: "Firefox", Class: DeviceBrowser},
+	{Pattern: "Safari", Class: DeviceBrowser},
+	{Pattern: "Edge", Class: DeviceBrowser},
+	{Pattern: "Browser", Class: DeviceBrowser},
+	{Pattern: "PC", Class: DeviceBrowser},
+	{Pattern: "Mac", Class: DeviceBrowser},
+	{Pattern: "Windows", Class: DeviceBrowser},
+}
+
+// ClassifyDevice returns the class of the first rule whose pattern is
+// contained in device.
+func ClassifyDevice(device string, rules []DeviceRule) string {
+	device = strings.TrimSpace(device)
+	if device == "" {
+		return DeviceUnknown
+	}
+	d := strings.ToLower(device)
+	for _, r := range rules {
+		if r.Pattern != "" && strings.Contains(d, strings.ToLower(r.Pattern)) {
+			return r.Class
+		}
+	}
+	return DeviceOther
+}
+
+type DeviceStat struct {
+	Class       string
+	Views       int
+	DurationMin int
+	Share       float64 // percent of the minutes of every class, "unknown" included
+	WeekdayMin  int
+	WeekendMin  int
+	TopGenres   []string // by minutes, up to 3
+}
+
+type deviceAgg struct {
+	DeviceStat
+	genres map[string]int
+}
+
+func addDeviceView(aggs map[string]*deviceAgg, class string, d time.Time, dur int, genres []string) {
+	a, ok := aggs[class]
+	if !ok {
+		a = &deviceAgg{DeviceStat: DeviceStat{Class: class}, genres: make(map[string]int)}
+		aggs[class] = a
+	}
+	a.Views++
+	a.DurationMin += dur
+	if wd := d.Weekday(); wd == time.Saturday || wd == time.Sunday {
+		a.WeekendMin += dur
+	} else {
+		a.WeekdayMin += dur
+	}
+	for _, g := range genres {
+		a.genres[g] += dur
+	}
+}
+
+func (s *Stats) computeDevices(aggs map[string]*deviceAgg) {
+	total := 0
+	for _, a := range aggs {
+		total += a.DurationMin
+	}
+
+	var ds []DeviceStat
+	for _, a := range aggs {
+		st := a.DeviceStat
+		if total > 0 {
+			st.Share = float64(st.DurationMin) / float64(total) * 100
+		}
+
+		names := make([]string, 0, len(a.genres))
+		for g := range a.genres {
+			names = append(names, g)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if a.genres[names[i]] != a.genres[names[j]] {
+				return a.genres[names[i]] > a.genres[names[j]]
+			}
+			return names[i] < names[j]
+		})
+		if len(names) > 3 {
+			names = names[:3]
+		}
+		for i, key := range names {
+			names[i] = genre.Name(key, s.Config.Lang)
+		}
+		st.TopGenres = names
+
+		ds = append(ds, st)
+	}
+
+	sort.Slice(ds, func(i, j int) bool {
+		if ds[i].DurationMin != ds[j].DurationMin {
+			return ds[i].DurationMin > ds[j].DurationMin
+		}
+		return ds[i].Class < ds[j].Class
+	})
+	s.DeviceStats = ds
+}
@@ -0,0 +1,99 @@
+package recap
+
+import (
+	"testing"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyDevice(t *testing.T) {
+	tests := []struct {
+		device   string
+		expected string
+	}{
+		{"Sony PS4", DeviceTV},
+		{"Samsung 2018 Smart TV", DeviceTV},
+		{"Google Chromecast V3 Streaming Stick", DeviceTV},
+		{"Android TV (Sony)", DeviceTV},
+		{"Apple iPhone 12", DevicePhone},
+		{"Android DefaultWidevineL3Phone", DevicePhone},
+		{"Apple iPad Pro 11 (Wi-Fi)", DeviceTablet},
+		{"Chrome PC (Cadmium)", DeviceBrowser},
+		{"Safari MAC (Cadmium)", DeviceBrowser},
+		{"Netflix Spatial Device", DeviceOther},
+		{"", DeviceUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.device, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ClassifyDevice(tt.device, DefaultDeviceRules))
+		})
+	}
+
+	t.Run("Custom rules first", func(t *testing.T) {
+		rules := append([]DeviceRule{{Pattern: "spatial", Class: "vr"}}, DefaultDeviceRules...)
+		assert.Equal(t, "vr", ClassifyDevice("Netflix Spatial Device", rules))
+	})
+}
+
+func TestComputeDevices(t *testing.T) {
+	item := func(date, device string, runtime int, genres ...string) build.BuiltItem {
+		return build.BuiltItem{
+			Date:       date,
+			Device:     device,
+			Normalized: model.NormalizedTitle{WorkTitle: "A", Type: "movie"},
+			Metadata:   &model.Metadata{Runtime: runtime, Genres: genres},
+		}
+	}
+	items := []build.BuiltItem{
+		item("2023-01-07", "Sony PS4", 120, "Action", "Drama"), // Saturday
+		item("2023-01-09", "Sony PS4", 60, "Drama"),            // Monday
+		item("2023-01-10", "Apple iPhone 12", 20, "Comedy"),    // Tuesday
+	}
+
+	s := ComputeStats(build.Built{Items: items}, 2023)
+
+	if assert.Len(t, s.DeviceStats, 2) {
+		tv := s.DeviceStats[0]
+		assert.Equal(t, DeviceTV, tv.Class)
+		assert.Equal(t, 2, tv.Views)
+		assert.Equal(t, 180, tv.DurationMin)
+		assert.Equal(t, 60, tv.WeekdayMin)
+		assert.Equal(t, 120, tv.WeekendMin)
+		assert.InDelta(t, 90.0, tv.Share, 0.01)
+		assert.Equal(t, []string{"ドラマ", "アクション"}, tv.TopGenres)
+
+		phone := s.DeviceStats[1]
+		assert.Equal(t, DevicePhone, phone.Class)
+		assert.Equal(t, []string{"コメディ"}, phone.TopGenres)
+	}
+	assert.Contains(t, RenderMarkdown(s), "| テレビ | 2 | 3.0 | 90.0% | 1.0 | 2.0 | ドラマ, アクション |")
+
+	// Without device types the section is omitted entirely
+	s = ComputeStats(build.Built{Items: []build.BuiltItem{item("2023-01-07", "", 10)}}, 2023)
+	assert.Empty(t, s.DeviceStats)
+	assert.NotContains(t, RenderMarkdown(s), "デバイス別")
+}
+
+func TestComputeDevicesTies(t *testing.T) {
+	item := func(device string) build.BuiltItem {
+		return build.BuiltItem{
+			Date:       "2023-01-07",
+			Device:     device,
+			Normalized: model.NormalizedTitle{WorkTitle: "A", Type: "movie"},
+			Metadata:   &model.Metadata{Runtime: 30},
+		}
+	}
+	items := []build.BuiltItem{item("Apple iPad"), item("Chrome PC"), item("Sony PS4"), item("")}
+
+	for range 10 {
+		s := ComputeStats(build.Built{Items: items}, 2023)
+		var classes []string
+		for _, d := range s.DeviceStats {
+			classes = append(classes, d.Class)
+			assert.InDelta(t, 25.0, d.Share, 0.01) // unknown counts towards the total
+		}
+		assert.Equal(t, []string{DeviceBrowser, DeviceTablet, DeviceTV, DeviceUnknown}, classes)
+	}
+}
@@ -0,0 +1,298 @@
+package recap
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportSchemaVersion is bumped on any incompatible change to Export or the
+// CSV columns. Adding fields is compatible.
+const ExportSchemaVersion = 1
+
+// Export is the machine-readable recap written by `recap --format json`.
+// Unlike Stats it has a stable schema: snake_case names, ISO 8601 dates
+// (YYYY-MM-DD) and months (YYYY-MM), and lists instead of maps. Sections
+// disabled in the report config are empty.
+type Export struct {
+	SchemaVersion int    `json:"schema_version"`
+	GeneratedAt   string `json:"generated_at"` // of the built JSON
+	Source        string `json:"source,omitempty"`
+
+	Period  ExportPeriod  `json:"period"`
+	Summary ExportSummary `json:"summary"`
+
+	Monthly    []ExportMonth      `json:"monthly"`
+	Weekdays   []ExportWeekday    `json:"weekdays"`
+	Streaks    []ExportSpan       `json:"streaks"`
+	Genres     []ExportGenre      `json:"genres"`
+	Titles     []ExportTitle      `json:"titles"` // every title, ordered by the configured sort key
+	Series     []ExportSeries     `json:"series"` // every series, ordered by the configured sort key
+	Unresolved []ExportUnresolved `json:"unresolved"`
+}
+
+type ExportPeriod struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Label string `json:"label"` // ISO 8601: "2025" for a calendar year, else "2025-04-01/2026-03-31"
+	Days  int    `json:"days"`
+}
+
+type ExportSummary struct {
+	Views           int     `json:"views"`
+	DurationMin     int     `json:"duration_min"`
+	ActiveDays      int     `json:"active_days"`
+	ActiveRatio     float64 `json:"active_ratio"` // percent of the days in the period
+	UnresolvedViews int     `json:"unresolved_views"`
+	CoverageRatio   float64 `json:"coverage_ratio"` // percent of views with metadata
+	FirstTimeViews  int     `json:"first_time_views"`
+	RepeatViews     int     `json:"repeat_views"`
+}
+
+type ExportMonth struct {
+	Month       string `json:"month"` // YYYY-MM
+	Views       int    `json:"views"`
+	DurationMin int    `json:"duration_min"`
+}
+
+type ExportWeekday struct {
+	Weekday     string `json:"weekday"` // "sunday" … "saturday"
+	Views       int    `json:"views"`
+	DurationMin int    `json:"duration_min"`
+}
+
+type ExportSpan struct {
+	Days  int    `json:"days"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type ExportGenre struct {
+	Key         string  `json:"key"`
+	Name        string  `json:"name"`
+	Views       int     `json:"views"`
+	DurationMin int     `json:"duration_min"`
+	Share       float64 `json:"share"` // percent of the total minutes
+}
+
+type ExportTitle struct {
+	Rank        int    `json:"rank"`
+	Title       string `json:"title"`
+	Type        string `json:"type"`
+	Views       int    `json:"views"`
+	DurationMin int    `json:"duration_min"`
+	PosterPath  string `json:"poster_path,omitempty"`
+}
+
+type ExportSeries struct {
+	Rank        int    `json:"rank"`
+	Name        string `json:"name"`
+	Views       int    `json:"views"`
+	DurationMin int    `json:"duration_min"`
+	FirstViewed string `json:"first_viewed"`
+	LastViewed  string `json:"last_viewed"`
+}
+
+type ExportUnresolved struct {
+	Title     string   `json:"title"`
+	Type      string   `json:"type"`
+	Views     int      `json:"views"`
+	RawTitles []string `json:"raw_titles,omitempty"`
+}
+
+// isoLabel is Period.Label in ISO 8601, without the localized separator.
+func isoLabel(p Period) string {
+	if p.IsCalendarYear() {
+		return p.Label()
+	}
+	return p.From.Format(dateLayout) + "/" + p.To.Format(dateLayout)
+}
+
+// NewExport converts s into the export schema.
+func NewExport(s Stats) Export {
+	s = s.WithoutDisabledSections()
+	cfg := s.Config.withDefaults()
+
+	e := Export{
+		SchemaVersion: ExportSchemaVersion,
+		GeneratedAt:   s.GeneratedAt,
+		Source:        s.SourceFile,
+		Period: ExportPeriod{
+			From:  s.Period.From.Format(dateLayout),
+			To:    s.Period.To.Format(dateLayout),
+			Label: isoLabel(s.Period),
+			Days:  s.PeriodDays,
+		},
+		Summary: ExportSummary{
+			Views:           s.TotalViews,
+			DurationMin:     s.TotalDurationMin,
+			ActiveDays:      s.ActiveDays,
+			UnresolvedViews: s.UnresolvedCount,
+			FirstTimeViews:  s.FirstTimeViews,
+			RepeatViews:     s.RepeatViews,
+		},
+		Monthly:    []ExportMonth{},
+		Weekdays:   []ExportWeekday{},
+		Streaks:    []ExportSpan{},
+		Genres:     []ExportGenre{},
+		Titles:     []ExportTitle{},
+		Series:     []ExportSeries{},
+		Unresolved: []ExportUnresolved{},
+	}
+	if s.PeriodDays > 0 {
+		e.Summary.ActiveRatio = float64(s.ActiveDays) / float64(s.PeriodDays) * 100
+	}
+	if s.TotalViews > 0 {
+		e.Summary.CoverageRatio = float64(s.TotalViews-s.UnresolvedCount) / float64(s.TotalViews) * 100
+	}
+
+	for _, m := range s.MonthlySeries {
+		e.Monthly = append(e.Monthly, ExportMonth{
+			Month:       fmt.Sprintf("%04d-%02d", m.Year, m.Month),
+			Views:       m.Views,
+			DurationMin: m.DurationMin,
+		})
+	}
+	if s.WeekdayStats != nil {
+		for wd := time.Sunday; wd <= time.Saturday; wd++ {
+			m := s.WeekdayStats[wd]
+			e.Weekdays = append(e.Weekdays, ExportWeekday{
+				Weekday:     strings.ToLower(wd.String()),
+				Views:       m.Views,
+				DurationMin: m.DurationMin,
+			})
+		}
+	}
+	for _, st := range s.TopStreaks {
+		e.Streaks = append(e.Streaks, ExportSpan{
+			Days:  st.Days,
+			Start: st.Start.Format(dateLayout),
+			End:   st.End.Format(dateLayout),
+		})
+	}
+	for _, g := range s.GenreStats {
+		e.Genres = append(e.Genres, ExportGenre{
+			Key:         g.Key,
+			Name:        g.Name,
+			Views:       g.Views,
+			DurationMin: g.DurationMin,
+			Share:       g.Share,
+		})
+	}
+
+	titles, series := s.RankedTitles(), s.Series
+	if cfg.SortBy == SortByViews {
+		series = seriesByViews(s.Series)
+	}
+	for i, t := range titles {
+		e.Titles = append(e.Titles, ExportTitle{
+			Rank:        i + 1,
+			Title:       t.Title,
+			Type:        t.Type,
+			Views:       t.Views,
+			DurationMin: t.DurationMin,
+			PosterPath:  t.PosterPath,
+		})
+	}
+	for i, ser := range series {
+		e.Series = append(e.Series, ExportSeries{
+			Rank:        i + 1,
+			Name:        ser.SeriesName,
+			Views:       ser.Views,
+			DurationMin: ser.DurationMin,
+			FirstViewed: ser.SpanStart.Format(dateLayout),
+			LastViewed:  ser.SpanEnd.Format(dateLayout),
+		})
+	}
+	for _, u := range s.UnresolvedList {
+		e.Unresolved = append(e.Unresolved, ExportUnresolved{
+			Title:     u.Title,
+			Type:      u.Type,
+			Views:     u.Views,
+			RawTitles: u.RawTitles,
+		})
+	}
+	return e
+}
+
+// MarshalExportJSON renders the export of s as indented JSON.
+func MarshalExportJSON(s Stats) ([]byte, error) {
+	b, err := json.MarshalIndent(NewExport(s), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// CSV tables written by WriteExportCSV, one file each.
+var csvTables = []string{"monthly", "genres", "titles", "series"}
+
+// WriteExportCSV writes the monthly, genre, title and series tables of the
+// export of s into dir as <table>.csv, with a header row of the JSON field
+// names. The files are UTF-8 with a byte order mark, which Excel needs to
+// read non-ASCII titles. The directory is created if needed.
+func WriteExportCSV(s Stats, dir string) error {
+	e := NewExport(s)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, name := range csvTables {
+		if err := writeCSVFile(filepath.Join(dir, name+".csv"), e.csvRows(name)); err != nil {
+			return fmt.Errorf("failed to write %s.csv: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// csvRows returns the header and rows of a table.
+func (e Export) csvRows(table string) [][]string {
+	itoa := strconv.Itoa
+	ftoa := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+
+	var rows [][]string
+	switch table {
+	case "monthly":
+		rows = append(rows, []string{"month", "views", "duration_min"})
+		for _, m := range e.Monthly {
+			rows = append(rows, []string{m.Month, itoa(m.Views), itoa(m.DurationMin)})
+		}
+	case "genres":
+		rows = append(rows, []string{"key", "name", "views", "duration_min", "share"})
+		for _, g := range e.Genres {
+			rows = append(rows, []string{g.Key, g.Name, itoa(g.Views), itoa(g.DurationMin), ftoa(g.Share)})
+		}
+	case "titles":
+		rows = append(rows, []string{"rank", "title", "type", "views", "duration_min", "poster_path"})
+		for _, t := range e.Titles {
+			rows = append(rows, []string{itoa(t.Rank), t.Title, t.Type, itoa(t.Views), itoa(t.DurationMin), t.PosterPath})
+		}
+	case "series":
+		rows = append(rows, []string{"rank", "name", "views", "duration_min", "first_viewed", "last_viewed"})
+		for _, ser := range e.Series {
+			rows = append(rows, []string{itoa(ser.Rank), ser.Name, itoa(ser.Views), itoa(ser.DurationMin), ser.FirstViewed, ser.LastViewed})
+		}
+	}
+	return rows
+}
+
+func writeCSVFile(path string, rows [][]string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString("\ufeff"); err != nil {
+		f.Close()
+		return err
+	}
+	w := csv.NewWriter(f)
+	if err := w.WriteAll(rows); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
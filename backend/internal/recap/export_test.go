@@ -0,0 +1,102 @@
+package recap
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/kmdkuk/nfrecap/internal/title"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportTestStats() Stats {
+	md := func(runtime int, genres ...string) *model.Metadata {
+		return &model.Metadata{Runtime: runtime, Genres: genres}
+	}
+	items := []build.BuiltItem{
+		{Date: "2024-01-06", Normalized: title.Normalize("Epic, Part 1"), Metadata: md(120, "Drama")},
+		{Date: "2024-01-07", Normalized: title.Normalize("Show: シーズン1: 第1話"), Metadata: md(30, "Comedy")},
+		{Date: "2024-01-08", Normalized: title.Normalize("Show: シーズン1: 第2話"), Metadata: md(30, "Comedy")},
+		{Date: "2024-03-01", Normalized: title.Normalize("Lost Film")},
+	}
+	return ComputeStats(build.Built{GeneratedAt: "2024-12-31T00:00:00Z", Items: items}, 2024)
+}
+
+func TestNewExport(t *testing.T) {
+	e := NewExport(exportTestStats())
+
+	assert.Equal(t, ExportSchemaVersion, e.SchemaVersion)
+	assert.Equal(t, ExportPeriod{From: "2024-01-01", To: "2024-12-31", Label: "2024", Days: 366}, e.Period)
+
+	fiscal, err := ParsePeriod("2024-04-01", "2025-03-31")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-04-01/2025-03-31", NewExport(Stats{Period: fiscal}).Period.Label)
+	assert.Equal(t, 4, e.Summary.Views)
+	assert.Equal(t, 180, e.Summary.DurationMin)
+	assert.Equal(t, 1, e.Summary.UnresolvedViews)
+	assert.InDelta(t, 75.0, e.Summary.CoverageRatio, 0.001)
+
+	require.Len(t, e.Monthly, 12)
+	assert.Equal(t, ExportMonth{Month: "2024-01", Views: 3, DurationMin: 180}, e.Monthly[0])
+	require.Len(t, e.Weekdays, 7)
+	assert.Equal(t, ExportWeekday{Weekday: "saturday", Views: 1, DurationMin: 120}, e.Weekdays[6])
+	assert.Equal(t, ExportSpan{Days: 3, Start: "2024-01-06", End: "2024-01-08"}, e.Streaks[0])
+
+	require.Len(t, e.Genres, 2)
+	assert.Equal(t, "drama", e.Genres[0].Key)
+	require.Len(t, e.Titles, 3)
+	assert.Equal(t, ExportTitle{Rank: 1, Title: "Epic, Part 1", Type: "movie", Views: 1, DurationMin: 120}, e.Titles[0])
+	assert.Equal(t, []ExportSeries{{Rank: 1, Name: "Show", Views: 2, DurationMin: 60, FirstViewed: "2024-01-07", LastViewed: "2024-01-08"}}, e.Series)
+	assert.Equal(t, "Lost Film", e.Unresolved[0].Title)
+
+	// snake_case keys, empty lists for disabled sections
+	s := exportTestStats()
+	s.Config.Sections = map[string]bool{SectionGenres: false}
+	s.Config.SortBy = SortByViews
+	e = NewExport(s)
+	assert.Empty(t, e.Genres)
+	assert.Equal(t, "Show", e.Titles[0].Title)
+
+	// every title, not only the ranking of the report
+	s = ComputeStatsWithOptions(build.Built{Items: []build.BuiltItem{
+		{Date: "2024-01-06", Normalized: title.Normalize("A")},
+		{Date: "2024-01-07", Normalized: title.Normalize("B")},
+		{Date: "2024-01-08", Normalized: title.Normalize("B")},
+	}}, Options{Period: YearPeriod(2024), Config: Config{Limits: Limits{Titles: 1}, SortBy: SortByViews}})
+	require.Len(t, s.TopTitlesByViews, 1)
+	ts := NewExport(s).Titles
+	require.Len(t, ts, 2)
+	assert.Equal(t, "B", ts[0].Title)
+	assert.Equal(t, 2, ts[1].Rank)
+
+	b, err := json.Marshal(e)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"schema_version":1`)
+	assert.Contains(t, string(b), `"genres":[]`)
+	assert.Contains(t, string(b), `"duration_min":`)
+}
+
+func TestWriteExportCSV(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	require.NoError(t, WriteExportCSV(exportTestStats(), dir))
+
+	for _, name := range []string{"monthly", "genres", "titles", "series"} {
+		assert.FileExists(t, filepath.Join(dir, name+".csv"))
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "titles.csv"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, "\ufeffrank,title,type,views,duration_min,poster_path", lines[0])
+	assert.Equal(t, `1,"Epic, Part 1",movie,1,120,`, lines[1])
+
+	b, err = os.ReadFile(filepath.Join(dir, "monthly.csv"))
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "\n2024-01,3,180\n")
+}
@@ -0,0 +1,50 @@
+package recap
+
+import "time"
+
+// genreTrendColumns is how many leading genres the Markdown trend table shows.
+const genreTrendColumns = 5
+
+// GenreTrend is one row of the genre × month matrix.
+type GenreTrend struct {
+	Key    string
+	Name   string
+	Months []GenreMonth // every month of the period in order, aligned with MonthlySeries
+}
+
+type GenreMonth struct {
+	Year  int
+	Month time.Month
+	Metric
+	Share float64 // percent of the month's total minutes
+}
+
+// computeGenreTrends fills GenreTrends and GenreMonthSpike from per-month
+// genre metrics keyed by the first day of the month. Must run after
+// computeGenres and after MonthlySeries is filled.
+func (s *Stats) computeGenreTrends(mm map[string]map[time.Time]Metric) {
+	s.GenreTrends = nil
+	s.GenreMonthSpike = make(map[string]Spike)
+
+	for _, g := range s.GenreStats {
+		t := GenreTrend{Key: g.Key, Name: g.Name}
+		var spike Spike
+		for _, ms := range s.MonthlySeries {
+			mk := time.Date(ms.Year, ms.Month, 1, 0, 0, 0, 0, time.UTC)
+			gm := GenreMonth{Year: ms.Year, Month: ms.Month, Metric: mm[g.Key][mk]}
+			if ms.DurationMin > 0 {
+				gm.Share = float64(gm.DurationMin) / float64(ms.DurationMin) * 100
+			}
+			t.Months = append(t.Months, gm)
+
+			// the earliest month wins ties
+			if gm.DurationMin > spike.DurationMin {
+				spike = Spike{Month: ms.Month, DurationMin: gm.DurationMin}
+			}
+		}
+		s.GenreTrends = append(s.GenreTrends, t)
+		if spike.DurationMin > 0 {
+			s.GenreMonthSpike[g.Key] = spike
+		}
+	}
+}
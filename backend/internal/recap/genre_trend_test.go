@@ -0,0 +1,51 @@
+package recap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeGenreTrends(t *testing.T) {
+	item := func(date string, runtime int, md model.Metadata) build.BuiltItem {
+		md.Provider = "tmdb"
+		md.Runtime = runtime
+		return build.BuiltItem{Date: date, Normalized: model.NormalizedTitle{WorkTitle: date, Type: "movie"}, Metadata: &md}
+	}
+	items := []build.BuiltItem{
+		// the same genre in different languages and with IDs merges into one key
+		item("2024-11-03", 60, model.Metadata{Genres: []string{"Action & Adventure"}, GenreIDs: []int{10759}}),
+		item("2024-11-10", 30, model.Metadata{Genres: []string{"アクション"}}),
+		item("2024-12-01", 30, model.Metadata{Genres: []string{"Drama"}}),
+		item("2024-12-02", 90, model.Metadata{Genres: []string{"Action", "Drama"}}),
+	}
+
+	p, err := ParsePeriod("2024-11-01", "2024-12-31")
+	require.NoError(t, err)
+	s := ComputeStatsWithOptions(build.Built{Items: items}, Options{Period: p})
+
+	require.Len(t, s.GenreTrends, 3)
+	action := s.GenreTrends[0]
+	assert.Equal(t, "action", action.Key)
+	assert.Equal(t, "アクション", action.Name)
+	require.Len(t, action.Months, 2)
+	assert.Equal(t, GenreMonth{Year: 2024, Month: time.November, Metric: Metric{Views: 2, DurationMin: 90}, Share: 100}, action.Months[0])
+	assert.Equal(t, Metric{Views: 1, DurationMin: 90}, action.Months[1].Metric)
+	assert.InDelta(t, 75.0, action.Months[1].Share, 1e-9)
+
+	assert.Equal(t, "drama", s.GenreTrends[1].Key)
+	assert.Equal(t, "adventure", s.GenreTrends[2].Key)
+	assert.Equal(t, 0, s.GenreTrends[1].Months[0].DurationMin)
+
+	// ties go to the earliest month
+	assert.Equal(t, Spike{Month: time.November, DurationMin: 90}, s.GenreMonthSpike["action"])
+	assert.Equal(t, Spike{Month: time.December, DurationMin: 120}, s.GenreMonthSpike["drama"])
+
+	md := RenderMarkdown(s)
+	assert.Contains(t, md, "| 月 | アクション | ドラマ | アドベンチャー |")
+	assert.Contains(t, md, "| 12月 | 75.0% | 100.0% | 0.0% |")
+}
@@ -0,0 +1,165 @@
+package recap
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+)
+
+// concentrationTitles is how many leading titles count towards
+// HabitStats.TopTitlesShare.
+const concentrationTitles = 5
+
+type HabitStats struct {
+	// Genre diversity: Shannon entropy of the minutes per genre, in bits.
+	// GenreEvenness divides it by the maximum possible entropy for the number
+	// of genres watched, so 1 means time was spread evenly.
+	GenreEntropy  float64
+	GenreEvenness float64
+
+	// Concentration: percent of the total minutes spent on the top titles.
+	TopTitlesShare float64
+
+	// Discovery: works watched for the first time ever.
+	Discovery     []DiscoveryMonth // every month of the period, aligned with MonthlySeries
+	NewWorks      int
+	Works         int
+	DiscoveryRate float64 // percent of the works watched in the period that were new
+
+	// Series of which only one episode was ever watched, with no view for
+	// abandonAfterDays, counted back like abandoned series (see asOf).
+	OneAndDoneSeries []string
+}
+
+type DiscoveryMonth struct {
+	Year     int
+	Month    time.Month
+	Works    int     // distinct works watched in the month
+	NewWorks int     // of which watched for the first time ever
+	Rate     float64 // percent
+}
+
+// computeHabits must run after computeGenres and computeTitles. Like
+// computeSeriesCompletion it looks at the whole history up to `to`, so
+// works first watched in an earlier period are not new.
+func (s *Stats) computeHabits(items []build.BuiltItem, from, to, now time.Time) {
+	h := HabitStats{}
+
+	// Genre diversity
+	genreTotal := 0
+	for _, g := range s.GenreStats {
+		genreTotal += g.DurationMin
+	}
+	if genreTotal > 0 {
+		n := 0
+		for _, g := range s.GenreStats {
+			if g.DurationMin == 0 {
+				continue
+			}
+			p := float64(g.DurationMin) / float64(genreTotal)
+			h.GenreEntropy -= p * math.Log2(p)
+			n++
+		}
+		if n > 1 {
+			h.GenreEvenness = h.GenreEntropy / math.Log2(float64(n))
+		}
+	}
+
+	// Concentration, over every title so that Limits.Titles does not matter
+	if s.TotalDurationMin > 0 {
+		top := 0
+		for i, t := range s.Titles {
+			if i >= concentrationTitles {
+				break
+			}
+			top += t.DurationMin
+		}
+		h.TopTitlesShare = float64(top) / float64(s.TotalDurationMin) * 100
+	}
+
+	// Discovery & one-and-done series
+	firstSeen := make(map[rewatchWork]time.Time)
+	seriesEpisodes := make(map[string]map[string]bool) // SeriesName -> episode keys
+	seriesLast := make(map[string]time.Time)
+	inPeriod := make(map[string]bool)
+	for _, it := range items {
+		d, err := time.Parse(dateLayout, it.Date)
+		if err != nil || d.After(to) {
+			continue
+		}
+		w := rewatchWork{title: it.Normalized.WorkTitle, typ: it.Normalized.Type}
+		if f, ok := firstSeen[w]; !ok || d.Before(f) {
+			firstSeen[w] = d
+		}
+
+		if w.typ != "tv" {
+			continue
+		}
+		if seriesEpisodes[w.title] == nil {
+			seriesEpisodes[w.title] = make(map[string]bool)
+		}
+		seriesEpisodes[w.title][episodeKey(it)] = true
+		if d.After(seriesLast[w.title]) {
+			seriesLast[w.title] = d
+		}
+		if !d.Before(from) {
+			inPeriod[w.title] = true
+		}
+	}
+
+	monthWorks := make(map[time.Time]map[rewatchWork]bool) // First day of month -> works
+	periodWorks := make(map[rewatchWork]bool)
+	for _, it := range items {
+		d, err := time.Parse(dateLayout, it.Date)
+		if err != nil || d.Before(from) || d.After(to) {
+			continue
+		}
+		w := rewatchWork{title: it.Normalized.WorkTitle, typ: it.Normalized.Type}
+		mk := time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if monthWorks[mk] == nil {
+			monthWorks[mk] = make(map[rewatchWork]bool)
+		}
+		monthWorks[mk][w] = true
+		periodWorks[w] = true
+	}
+
+	for _, ms := range s.MonthlySeries {
+		mk := time.Date(ms.Year, ms.Month, 1, 0, 0, 0, 0, time.UTC)
+		dm := DiscoveryMonth{Year: ms.Year, Month: ms.Month, Works: len(monthWorks[mk])}
+		for w := range monthWorks[mk] {
+			f := firstSeen[w]
+			if f.Year() == ms.Year && f.Month() == ms.Month {
+				dm.NewWorks++
+			}
+		}
+		if dm.Works > 0 {
+			dm.Rate = float64(dm.NewWorks) / float64(dm.Works) * 100
+		}
+		h.Discovery = append(h.Discovery, dm)
+	}
+
+	h.Works = len(periodWorks)
+	for w := range periodWorks {
+		if !firstSeen[w].Before(from) {
+			h.NewWorks++
+		}
+	}
+	if h.Works > 0 {
+		h.DiscoveryRate = float64(h.NewWorks) / float64(h.Works) * 100
+	}
+
+	for sn, eps := range seriesEpisodes {
+		if !inPeriod[sn] || len(eps) != 1 {
+			continue
+		}
+		if now.Sub(seriesLast[sn]) < abandonAfterDays*24*time.Hour {
+			continue // may still come back
+		}
+		h.OneAndDoneSeries = append(h.OneAndDoneSeries, sn)
+	}
+	sort.Strings(h.OneAndDoneSeries)
+
+	s.Habits = h
+}
@@ -0,0 +1,102 @@
+package recap
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeHabits(t *testing.T) {
+	movie := func(date, title string, runtime int, genres ...string) build.BuiltItem {
+		return build.BuiltItem{
+			Date:       date,
+			Normalized: model.NormalizedTitle{WorkTitle: title, Type: "movie"},
+			Metadata:   &model.Metadata{Runtime: runtime, Genres: genres},
+		}
+	}
+	episode := func(date, series string, ep int) build.BuiltItem {
+		return build.BuiltItem{
+			Date:       date,
+			Normalized: model.NormalizedTitle{WorkTitle: series, Type: "tv", SeasonNumber: 1, EpisodeNumber: ep},
+			Metadata:   &model.Metadata{Runtime: 10},
+		}
+	}
+	items := []build.BuiltItem{
+		movie("2023-12-20", "Old", 100, "Drama"), // before the period
+		movie("2024-01-05", "Old", 100, "Drama"),
+		movie("2024-01-06", "New1", 100, "Comedy"),
+		movie("2024-02-01", "New2", 100, "Action"),
+		movie("2024-02-02", "New3", 100, "Horror"),
+		movie("2024-02-03", "New4", 100, "Romance"),
+		movie("2024-03-01", "New5", 20, "Drama"),
+		episode("2024-01-10", "Pilot Only", 1),
+		episode("2024-01-10", "Pilot Only", 1), // same episode again
+		episode("2024-01-11", "Binged", 1),
+		episode("2024-01-12", "Binged", 2),
+		episode("2024-03-01", "Too Recent", 1),
+		movie("2024-04-20", "After", 100, "Drama"), // after the period: 60 days count back from March 31
+	}
+
+	p, err := ParsePeriod("2024-01-01", "2024-03-31")
+	require.NoError(t, err)
+	s := ComputeStatsWithOptions(build.Built{Items: items}, Options{Period: p})
+	h := s.Habits
+
+	// Drama 120, Comedy/Action/Horror/Romance 100 each, of 520
+	want := 0.0
+	for _, m := range []float64{120, 100, 100, 100, 100} {
+		p := m / 520
+		want -= p * math.Log2(p)
+	}
+	assert.InDelta(t, want, h.GenreEntropy, 1e-9)
+	assert.InDelta(t, want/math.Log2(5), h.GenreEvenness, 1e-9)
+
+	// top 5 titles: five 100 min movies of 570 min
+	assert.InDelta(t, 500.0/570*100, h.TopTitlesShare, 1e-9)
+	// … whatever the number of titles in the report
+	capped := ComputeStatsWithOptions(build.Built{Items: items}, Options{Period: p, Config: Config{Limits: Limits{Titles: 2}}})
+	assert.InDelta(t, h.TopTitlesShare, capped.Habits.TopTitlesShare, 1e-9)
+
+	assert.Equal(t, 9, h.Works)
+	assert.Equal(t, 8, h.NewWorks)
+	require.Len(t, h.Discovery, 3)
+	assert.Equal(t, DiscoveryMonth{Year: 2024, Month: time.January, Works: 4, NewWorks: 3, Rate: 75}, h.Discovery[0])
+	assert.Equal(t, DiscoveryMonth{Year: 2024, Month: time.February, Works: 3, NewWorks: 3, Rate: 100}, h.Discovery[1])
+	assert.Equal(t, 2, h.Discovery[2].NewWorks)
+
+	assert.Equal(t, []string{"Pilot Only"}, h.OneAndDoneSeries)
+
+	md := RenderMarkdown(s)
+	assert.Contains(t, md, "| 新しい作品との出会い | 8 / 9 作品（88.9%） |")
+	assert.Contains(t, md, "> 1話だけで離れたシリーズ：Pilot Only")
+}
+
+func TestComputeHabitsEmpty(t *testing.T) {
+	s := ComputeStats(build.Built{}, 2024)
+	assert.Zero(t, s.Habits.GenreEntropy)
+	assert.Zero(t, s.Habits.TopTitlesShare)
+	assert.Len(t, s.Habits.Discovery, 12)
+	assert.Empty(t, s.Habits.OneAndDoneSeries)
+}
+
+func TestOneAndDoneSeriesMidYear(t *testing.T) {
+	episode := func(date, series string) build.BuiltItem {
+		return build.BuiltItem{
+			Date:       date,
+			Normalized: model.NormalizedTitle{WorkTitle: series, Type: "tv", SeasonNumber: 1, EpisodeNumber: 1},
+		}
+	}
+	// Exported in March: the pilot in January may still be followed up.
+	items := []build.BuiltItem{
+		episode("2024-01-02", "Gone"),
+		episode("2024-02-01", "Recent"),
+		episode("2024-03-15", "Latest"),
+	}
+	s := ComputeStats(build.Built{Items: items}, 2024)
+	assert.Equal(t, []string{"Gone"}, s.Habits.OneAndDoneSeries)
+}
@@ -0,0 +1,165 @@
+package recap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Mermaid charts for the Markdown report, rendered by GitHub and most note
+// apps. Each returns a ```mermaid fenced block without a trailing newline,
+// or "" when there is nothing to draw. Numbers are written without digit
+// grouping whatever the report language, as Mermaid expects.
+
+// mermaidCharts are the Mermaid charts available to templates by name.
+var mermaidCharts = map[string]func(Stats) string{
+	"monthly": RenderMonthlyMermaid,
+	"weekday": RenderWeekdayMermaid,
+	"genres":  RenderGenrePieMermaid,
+	"series":  RenderSeriesGanttMermaid,
+}
+
+func renderMermaid(s Stats, name string) (string, error) {
+	chart, ok := mermaidCharts[name]
+	if !ok {
+		return "", fmt.Errorf("unknown mermaid chart %q", name)
+	}
+	return chart(s), nil
+}
+
+// RenderMonthlyMermaid draws estimated hours per month as an xychart-beta
+// bar chart.
+func RenderMonthlyMermaid(s Stats) string {
+	if s.TotalViews == 0 {
+		return ""
+	}
+	p := s.printer()
+	singleYear := s.Period.From.Year() == s.Period.To.Year()
+	labels := make([]string, len(s.MonthlySeries))
+	values := make([]float64, len(s.MonthlySeries))
+	for i, m := range s.MonthlySeries {
+		labels[i] = p.Month(m.Month)
+		if !singleYear {
+			labels[i] = p.YearMonth(m.Year, m.Month)
+		}
+		values[i] = float64(m.DurationMin) / 60.0
+	}
+	return mermaidBarChart(p.T("html.monthly"), p.T("col.hours"), labels, values)
+}
+
+// RenderWeekdayMermaid draws estimated hours per weekday, Sunday first, as
+// an xychart-beta bar chart.
+func RenderWeekdayMermaid(s Stats) string {
+	if s.TotalViews == 0 {
+		return ""
+	}
+	p := s.printer()
+	var labels []string
+	var values []float64
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		labels = append(labels, p.Weekday(wd))
+		values = append(values, float64(s.WeekdayStats[wd].DurationMin)/60.0)
+	}
+	return mermaidBarChart(p.T("html.weekday"), p.T("col.hours"), labels, values)
+}
+
+func mermaidBarChart(title, yLabel string, labels []string, values []float64) string {
+	quoted := make([]string, len(labels))
+	nums := make([]string, len(values))
+	for i, l := range labels {
+		quoted[i] = strconv.Quote(mermaidText(l))
+	}
+	for i, v := range values {
+		nums[i] = mermaidNumber(v)
+	}
+
+	var b strings.Builder
+	b.WriteString("```mermaid\nxychart-beta\n")
+	fmt.Fprintf(&b, "    title %q\n", mermaidText(title))
+	fmt.Fprintf(&b, "    x-axis [%s]\n", strings.Join(quoted, ", "))
+	fmt.Fprintf(&b, "    y-axis %q\n", mermaidText(yLabel))
+	fmt.Fprintf(&b, "    bar [%s]\n", strings.Join(nums, ", "))
+	b.WriteString("```")
+	return b.String()
+}
+
+// RenderGenrePieMermaid draws the estimated hours per genre as a pie chart:
+// the top Limits.Genres genres and the rest as "other", like the genre table.
+func RenderGenrePieMermaid(s Stats) string {
+	limit := s.Config.withDefaults().Limits.Genres
+	p := s.printer()
+	total := 0
+	for _, g := range s.GenreStats {
+		total += g.DurationMin
+	}
+	if total == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("```mermaid\npie\n")
+	fmt.Fprintf(&b, "    title %s\n", mermaidText(p.T("html.genres")))
+	other := 0
+	for i, g := range s.GenreStats {
+		if i >= limit {
+			other += g.DurationMin
+			continue
+		}
+		if g.DurationMin > 0 {
+			fmt.Fprintf(&b, "    %q : %s\n", mermaidText(g.Name), mermaidNumber(float64(g.DurationMin)/60.0))
+		}
+	}
+	if other > 0 {
+		fmt.Fprintf(&b, "    %q : %s\n", mermaidText(p.T("other")), mermaidNumber(float64(other)/60.0))
+	}
+	b.WriteString("```")
+	return b.String()
+}
+
+// RenderSeriesGanttMermaid draws the viewing span (first to last day
+// watched) of the top series by the configured sort key as a gantt chart.
+func RenderSeriesGanttMermaid(s Stats) string {
+	cfg := s.Config.withDefaults()
+	series := s.TopSeriesByDuration
+	if cfg.SortBy == SortByViews {
+		series = s.TopSeriesByViews
+	}
+	series = series[:min(len(series), cfg.Limits.TitlesShown)]
+	if len(series) == 0 {
+		return ""
+	}
+	p := s.printer()
+
+	var b strings.Builder
+	b.WriteString("```mermaid\ngantt\n")
+	fmt.Fprintf(&b, "    title %s\n", mermaidText(p.T("mermaid.series_spans")))
+	b.WriteString("    dateFormat YYYY-MM-DD\n")
+	b.WriteString("    axisFormat %Y-%m\n")
+	for _, ser := range series {
+		// Spans are inclusive; gantt end dates are not.
+		fmt.Fprintf(&b, "    %s : %s, %s\n", mermaidText(ser.SeriesName),
+			ser.SpanStart.Format(dateLayout), ser.SpanEnd.AddDate(0, 0, 1).Format(dateLayout))
+	}
+	b.WriteString("```")
+	return b.String()
+}
+
+// mermaidEscaper replaces the characters that end a label or statement with
+// Mermaid entity codes, which are shown as the characters themselves.
+var mermaidEscaper = strings.NewReplacer(
+	"#", "#35;",
+	`"`, "#quot;",
+	":", "#58;",
+	";", "#59;",
+	"\n", " ",
+	"\r", " ",
+)
+
+func mermaidText(s string) string {
+	return mermaidEscaper.Replace(s)
+}
+
+func mermaidNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', 1, 64)
+}
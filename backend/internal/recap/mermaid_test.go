@@ -0,0 +1,81 @@
+package recap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMonthlyMermaid(t *testing.T) {
+	out := RenderMonthlyMermaid(exportTestStats())
+
+	assert.True(t, strings.HasPrefix(out, "```mermaid\nxychart-beta\n"), out)
+	assert.True(t, strings.HasSuffix(out, "\n```"), out)
+	assert.Contains(t, out, `    title "月別の推定視聴時間"`)
+	assert.Contains(t, out, `    x-axis ["1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"]`)
+	assert.Contains(t, out, "    bar [3.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0]")
+
+	assert.Empty(t, RenderMonthlyMermaid(ComputeStats(build.Built{}, 2024)))
+}
+
+func TestRenderWeekdayMermaid(t *testing.T) {
+	s := exportTestStats()
+	s.Config.Lang = "en"
+	out := RenderWeekdayMermaid(s)
+
+	assert.Contains(t, out, `    x-axis ["Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"]`)
+	// 2024-01-06 is a Saturday, 01-07 a Sunday and 01-08 a Monday
+	assert.Contains(t, out, "    bar [0.5, 0.5, 0.0, 0.0, 0.0, 0.0, 2.0]")
+}
+
+func TestRenderGenrePieMermaid(t *testing.T) {
+	s := exportTestStats()
+	assert.Equal(t, "```mermaid\npie\n    title ジャンル別 推定視聴時間\n    \"ドラマ\" : 2.0\n    \"コメディ\" : 1.0\n```", RenderGenrePieMermaid(s))
+
+	s.Config.Limits.Genres = 1
+	assert.Contains(t, RenderGenrePieMermaid(s), "    \"その他\" : 1.0\n")
+
+	assert.Empty(t, RenderGenrePieMermaid(ComputeStats(build.Built{}, 2024)))
+}
+
+func TestRenderSeriesGanttMermaid(t *testing.T) {
+	out := RenderSeriesGanttMermaid(exportTestStats())
+
+	assert.Contains(t, out, "    dateFormat YYYY-MM-DD\n")
+	// the end is exclusive, the day after the last view
+	assert.Contains(t, out, "    Show : 2024-01-07, 2024-01-09\n")
+
+	assert.Empty(t, RenderSeriesGanttMermaid(ComputeStats(build.Built{}, 2024)))
+}
+
+func TestMermaidText(t *testing.T) {
+	assert.Equal(t, "Re#58;Zero #35;1 #quot;A#quot;#59; B", mermaidText("Re:Zero #1 \"A\"; B"))
+	assert.Equal(t, "a b", mermaidText("a\nb"))
+}
+
+func TestRenderMarkdownMermaid(t *testing.T) {
+	s := exportTestStats()
+	assert.NotContains(t, RenderMarkdown(s), "```mermaid")
+
+	md := RenderMarkdownWithOptions(s, MarkdownOptions{Mermaid: true})
+	assert.Equal(t, 4, strings.Count(md, "```mermaid"))
+	for _, chart := range []string{"xychart-beta", "pie", "gantt"} {
+		assert.Contains(t, md, "```mermaid\n"+chart+"\n")
+	}
+	// right below its table
+	monthly := md[strings.Index(md, "### 月別"):]
+	assert.Less(t, strings.Index(monthly, "xychart-beta"), strings.Index(monthly, "\n###"))
+
+	s.Config.Sections = map[string]bool{SectionGenres: false, SectionSeries: false}
+	md = RenderMarkdownWithOptions(s, MarkdownOptions{Mermaid: true})
+	assert.Equal(t, 2, strings.Count(md, "```mermaid"))
+
+	out, err := RenderMarkdownTemplate(s, `{{mermaid "genres"}}`)
+	require.NoError(t, err)
+	assert.Contains(t, out, "```mermaid\npie\n")
+	_, err = RenderMarkdownTemplate(s, `{{mermaid "calendar"}}`)
+	assert.ErrorContains(t, err, `unknown mermaid chart "calendar"`)
+}
@@ -0,0 +1,79 @@
+package recap
+
+import (
+	"fmt"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Period is an inclusive range of calendar days. Both ends are dates at
+// midnight UTC, matching how built JSON dates are parsed.
+type Period struct {
+	From time.Time
+	To   time.Time
+}
+
+// YearPeriod returns the period covering the whole calendar year.
+func YearPeriod(year int) Period {
+	return Period{
+		From: time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// ParsePeriod parses "YYYY-MM-DD" bounds. An empty `to` means today.
+func ParsePeriod(from, to string) (Period, error) {
+	f, err := time.Parse(dateLayout, from)
+	if err != nil {
+		return Period{}, fmt.Errorf("invalid from date %q: %w", from, err)
+	}
+	var t time.Time
+	if to == "" {
+		now := time.Now()
+		t = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	} else {
+		t, err = time.Parse(dateLayout, to)
+		if err != nil {
+			return Period{}, fmt.Errorf("invalid to date %q: %w", to, err)
+		}
+	}
+	if t.Before(f) {
+		return Period{}, fmt.Errorf("to date %s is before from date %s", t.Format(dateLayout), f.Format(dateLayout))
+	}
+	return Period{From: f, To: t}, nil
+}
+
+// Contains reports whether d falls on a day within the period.
+func (p Period) Contains(d time.Time) bool {
+	return !d.Before(p.From) && !d.After(p.To)
+}
+
+// Days returns the number of calendar days in the period, both ends included.
+func (p Period) Days() int {
+	return int(p.To.Sub(p.From).Hours()/24) + 1
+}
+
+// IsCalendarYear reports whether the period is exactly one calendar year.
+func (p Period) IsCalendarYear() bool {
+	return p == YearPeriod(p.From.Year())
+}
+
+// Label is "2025" for a calendar year and "2025-04-01 〜 2026-03-31" otherwise.
+func (p Period) Label() string {
+	if p.IsCalendarYear() {
+		return fmt.Sprintf("%d", p.From.Year())
+	}
+	return fmt.Sprintf("%s 〜 %s", p.From.Format(dateLayout), p.To.Format(dateLayout))
+}
+
+// months returns the first day of every month touched by the period, in order.
+func (p Period) months() []time.Time {
+	var ms []time.Time
+	cur := time.Date(p.From.Year(), p.From.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for !cur.After(p.To) {
+		ms = append(ms, cur)
+		cur = cur.AddDate(0, 1, 0)
+	}
+	return ms
+}
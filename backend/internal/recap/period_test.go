@@ -0,0 +1,73 @@
+package recap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriod(t *testing.T) {
+	t.Run("Calendar Year", func(t *testing.T) {
+		assert.Equal(t, 365, YearPeriod(2023).Days())
+		assert.Equal(t, 366, YearPeriod(2024).Days())
+		assert.True(t, YearPeriod(2024).IsCalendarYear())
+		assert.Equal(t, "2024", YearPeriod(2024).Label())
+	})
+
+	t.Run("Custom Range", func(t *testing.T) {
+		p, err := ParsePeriod("2024-02-28", "2024-03-01")
+		require.NoError(t, err)
+		assert.Equal(t, 3, p.Days()) // includes Feb 29
+		assert.False(t, p.IsCalendarYear())
+		assert.Equal(t, "2024-02-28 〜 2024-03-01", p.Label())
+		assert.True(t, p.Contains(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)))
+		assert.False(t, p.Contains(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := ParsePeriod("2024-13-01", "2024-12-31")
+		assert.ErrorContains(t, err, "invalid from date")
+		_, err = ParsePeriod("2024-04-01", "2024-03-31")
+		assert.ErrorContains(t, err, "before from date")
+	})
+
+	t.Run("Months", func(t *testing.T) {
+		p, err := ParsePeriod("2024-11-15", "2025-02-01")
+		require.NoError(t, err)
+		ms := p.months()
+		if assert.Len(t, ms, 4) {
+			assert.Equal(t, "2024-11-01", ms[0].Format(dateLayout))
+			assert.Equal(t, "2025-02-01", ms[3].Format(dateLayout))
+		}
+	})
+}
+
+func TestComputeStatsWithOptions_Range(t *testing.T) {
+	items := []build.BuiltItem{
+		{Date: "2024-03-31", Normalized: model.NormalizedTitle{WorkTitle: "Before"}, Metadata: &model.Metadata{Runtime: 10}},
+		{Date: "2024-04-01", Normalized: model.NormalizedTitle{WorkTitle: "Start"}, Metadata: &model.Metadata{Runtime: 10}},
+		{Date: "2025-03-31", Normalized: model.NormalizedTitle{WorkTitle: "End"}, Metadata: &model.Metadata{Runtime: 20}},
+		{Date: "2025-04-01", Normalized: model.NormalizedTitle{WorkTitle: "After"}, Metadata: &model.Metadata{Runtime: 10}},
+	}
+	p, err := ParsePeriod("2024-04-01", "2025-03-31")
+	require.NoError(t, err)
+
+	s := ComputeStatsWithOptions(build.Built{Items: items}, Options{Period: p})
+
+	assert.Equal(t, 2, s.TotalViews)
+	assert.Equal(t, 30, s.TotalDurationMin)
+	assert.Equal(t, 365, s.PeriodDays)
+	assert.Equal(t, 2024, s.Year)
+	if assert.Len(t, s.MonthlySeries, 12) {
+		assert.Equal(t, MonthMetric{Year: 2024, Month: time.April, Metric: Metric{Views: 1, DurationMin: 10}}, s.MonthlySeries[0])
+		assert.Equal(t, MonthMetric{Year: 2025, Month: time.March, Metric: Metric{Views: 1, DurationMin: 20}}, s.MonthlySeries[11])
+	}
+
+	vd := prepareViewData(s)
+	assert.Equal(t, "0.5", vd.ActiveRatio) // 2 / 365
+	assert.Equal(t, "2024年4月", vd.MonthlyRows[0].Month)
+}
@@ -0,0 +1,70 @@
+package recap
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// RenderAllTimeMarkdown renders the built-in all-time Markdown report.
+func RenderAllTimeMarkdown(a AllTimeStats) (string, error) {
+	data := prepareAllTimeViewData(a)
+
+	t, err := template.New("alltime").Funcs(templateFuncs(a.Lifetime.printer())).Parse(BuiltinTemplate(allTimeTemplateFile))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+type allTimeViewData struct {
+	viewData
+	From     string
+	To       string
+	YearRows []yearRow
+}
+
+type yearRow struct {
+	Year        int
+	Views       int
+	Hours       string
+	ActiveDays  int
+	ActiveRatio string
+	TopGenre    string
+	TopSeries   string
+}
+
+func prepareAllTimeViewData(a AllTimeStats) allTimeViewData {
+	p := a.Lifetime.printer()
+	vd := allTimeViewData{viewData: prepareViewData(a.Lifetime)}
+	vd.GeneratedAt = a.GeneratedAt
+	if len(a.Years) > 0 {
+		vd.From = p.Date(a.Lifetime.Period.From)
+		vd.To = p.Date(a.Lifetime.Period.To)
+	}
+
+	for _, y := range a.Years {
+		vd.YearRows = append(vd.YearRows, yearRow{
+			Year:        y.Year,
+			Views:       y.Views,
+			Hours:       p.Hours(y.DurationMin),
+			ActiveDays:  y.ActiveDays,
+			ActiveRatio: p.Float(y.ActiveRatio, 1),
+			TopGenre:    orDash(y.TopGenre),
+			TopSeries:   orDash(y.TopSeries),
+		})
+	}
+	return vd
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
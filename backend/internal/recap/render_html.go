@@ -0,0 +1,48 @@
+package recap
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// HTMLOptions controls RenderHTML.
+type HTMLOptions struct {
+	// PosterDir holds poster images named after the base name of
+	// TitleStat.PosterPath / SeriesStat.PosterPath (see `nfrecap build
+	// --poster-dir`). Posters are embedded when found; empty means none.
+	PosterDir string
+}
+
+// RenderHTML renders the recap as a single self-contained HTML page: styles,
+// SVG charts and posters are all inline, so the file can be mailed or hosted
+// as is. Nothing is fetched from the network. Like RenderMarkdown it writes
+// a rendering error into the page; see RenderHTMLTemplate.
+func RenderHTML(s Stats, opts HTMLOptions) string {
+	out, err := RenderHTMLTemplate(s, opts, BuiltinTemplate(HTMLTemplateFile))
+	if err != nil {
+		return fmt.Sprintf("Error rendering report: %v", err)
+	}
+	return out
+}
+
+// posterDataURI returns the poster for posterPath in dir as a data URI, or ""
+// if there is none.
+func posterDataURI(dir, posterPath string) template.URL {
+	if dir == "" || posterPath == "" {
+		return ""
+	}
+	b, err := os.ReadFile(filepath.Join(dir, filepath.Base(posterPath)))
+	if err != nil {
+		return ""
+	}
+	mime := http.DetectContentType(b)
+	if !strings.HasPrefix(mime, "image/") {
+		return ""
+	}
+	return template.URL("data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(b))
+}
@@ -0,0 +1,71 @@
+package recap
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/kmdkuk/nfrecap/internal/title"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderHTML(t *testing.T) {
+	dir := t.TempDir()
+	var img bytes.Buffer
+	require.NoError(t, png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 2, 3))))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "epic.png"), img.Bytes(), 0644))
+
+	md := func(runtime int, poster string, genres ...string) *model.Metadata {
+		return &model.Metadata{Runtime: runtime, PosterPath: poster, Genres: genres}
+	}
+	items := []build.BuiltItem{
+		{Date: "2024-01-01", Normalized: title.Normalize("Epic"), Metadata: md(120, "/epic.png", "Action")},
+		{Date: "2024-03-02", Normalized: title.Normalize("<script>alert(1)</script>"), Metadata: md(90, "/missing.jpg", "Drama")},
+		{Date: "2024-03-03", Normalized: title.Normalize("Lost Film")},
+	}
+	s := ComputeStats(build.Built{Items: items}, 2024)
+	require.Equal(t, "/epic.png", s.TopTitlesByDuration[0].PosterPath)
+
+	out := RenderHTML(s, HTMLOptions{PosterDir: dir})
+
+	assert.True(t, strings.HasPrefix(out, "<!DOCTYPE html>"))
+	assert.Equal(t, 4, strings.Count(out, "<svg "), "monthly, weekday, calendar and genre charts")
+	assert.Contains(t, out, `<img src="data:image/png;base64,`)
+	// both rankings show Epic with its poster; the others get a placeholder
+	assert.Equal(t, 2, strings.Count(out, "<img "))
+	assert.Equal(t, 4, strings.Count(out, `<div class="noimg">`))
+	assert.NotContains(t, out, "<script>")
+	assert.Contains(t, out, "&lt;script&gt;")
+	assert.NotContains(t, out, `src="http`)
+	assert.NotContains(t, out, "Error")
+
+	// sections follow the report config
+	s.Config.Sections = map[string]bool{SectionCalendar: false, SectionGenres: false}
+	out = RenderHTML(s, HTMLOptions{})
+	assert.Equal(t, 2, strings.Count(out, "<svg "))
+	assert.NotContains(t, out, "<img ")
+}
+
+func TestRenderGenreDonutSVG(t *testing.T) {
+	s := Stats{
+		Config: Config{Limits: Limits{Genres: 1}},
+		GenreStats: []GenreStat{
+			{Key: "drama", Name: "ドラマ", DurationMin: 90},
+			{Key: "comedy", Name: "コメディ", DurationMin: 20},
+			{Key: "horror", Name: "ホラー", DurationMin: 10},
+		},
+	}
+	svg := RenderGenreDonutSVG(s)
+	assert.Equal(t, 2, strings.Count(svg, "<circle "))
+	assert.Contains(t, svg, "ドラマ 75.0%")
+	assert.Contains(t, svg, "その他 25.0%")
+
+	assert.Empty(t, RenderGenreDonutSVG(Stats{}))
+}
@@ -1,181 +1,55 @@
 package recap
 
 import (
-	"bytes"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
-	"text/template"
 	"time"
-)
-
-func RenderMarkdown(s Stats) string {
-	tmplStr := `
-# Netflix Recap {{.Year}}
-
-> 生成日時: {{.GeneratedAt}}
-
----
-
-## まずは要点（TL;DR）
-
-- 推定総視聴時間：**{{.TotalDurationHours}} 時間**（{{.TotalDurationMin}} 分）
-- 視聴回数：**{{.TotalViews}} 本**
-- 視聴日数：**{{.ActiveDays}} 日**（{{.ActiveRatio}}%）
-- 最長連続視聴：**{{.LongestStreakDays}} 日**（{{.LongestStreakStart}} 〜 {{.LongestStreakEnd}}）
-- メタデータ取得率：**{{.CoveredViews}} / {{.TotalViews}}**（{{.CoverageRatio}}%）
-
----
-
-## 1. 全体概要
-
-### 月別 推定視聴時間・視聴回数
-
-| 月 | 視聴回数 | 推定視聴時間（時間） |
-|---:|---:|---:|
-{{- range .MonthlyRows }}
-| {{.Month}} | {{.Views}} | {{.Hours}} |
-{{- end }}
-
----
-
-### 曜日別 視聴傾向
-
-| 曜日 | 視聴回数 | 推定視聴時間（時間） |
-|---|---:|---:|
-{{- range .WeekdayRows }}
-| {{.Weekday}} | {{.Views}} | {{.Hours}} |
-{{- end }}
-
-> ※ 推定視聴時間は ` + "`runtime_min`" + ` を基に算出しています
-> ※ TV シリーズの場合、1話あたりの代表的な再生時間を使用しています
-
----
-
-## 2. 視聴の継続性（Streak）
-
-### 最長連続視聴記録
-
-- **{{.LongestStreakDays}} 日連続**
-- 期間：{{.LongestStreakStart}} 〜 {{.LongestStreakEnd}}
-
----
-
-### 連続視聴ランキング（Top 3）
-
-| 順位 | 連続日数 | 期間 |
-|---:|---:|---|
-{{- range .TopStreaksRows }}
-| {{.Rank}}位 | {{.Days}} 日 | {{.Start}} 〜 {{.End}} |
-{{- end }}
-
----
-
-### 視聴活動の間隔
-
-- 視聴があった日数：{{.ActiveDays}} 日
-- 最長の空白期間：{{.MaxGapDays}} 日
-  （{{.MaxGapStart}} 〜 {{.MaxGapEnd}}）
-
----
-
-## 3. ジャンル別分析（時間ベース）
-
-### ジャンル別 推定視聴時間（Top 10 + その他）
-
-| ジャンル | 推定視聴時間（時間） | 割合 | 視聴回数 |
-|---|---:|---:|---:|
-{{- range .GenreRows }}
-| {{.Name}} | {{.Hours}} | {{.Share}}% | {{.Views}} |
-{{- end }}
-
----
-
-### ジャンルの偏り（月別ピーク）
-
-| ジャンル | 最多視聴月 | ピーク時間（時間） | 備考 |
-|---|---|---:|---|
-{{- range .GenreSpikeRows }}
-| {{.Name}} | {{.Month}} | {{.Hours}} | {{.Note}} |
-{{- end }}
-
----
-
-## 4. 作品・シリーズ別
-
-
----
-
-
-### ジャンル別視聴映画（サンプル）
-
-| ジャンル | 代表的な視聴作品（3選） |
-|---|---|
-{{- range .GenreRows }}
-{{- if .SampleMovies }}
-| {{.Name}} | {{.SampleMovies}} |
-{{- end }}
-{{- end }}
-
----
-
-### 推定視聴時間が多い作品（Top）
-
-| 順位 | 作品名 | 種別 | 推定視聴時間（時間） | 視聴回数 |
-|---:|---|---|---:|---:|
-{{- range .TopTitlesByDurationRows }}
-| {{.Rank}}位 | {{.Title}} | {{.Type}} | {{.Hours}} | {{.Views}} |
-{{- end }}
-
-
----
-
-### シリーズ視聴（TV作品のみ）
-
-| 順位 | シリーズ名 | 視聴回数 | 推定視聴時間（時間） | 集中視聴期間 |
-|---:|---|---:|---:|---|
-{{- range .TopSeriesRows }}
-| {{.Rank}}位 | {{.SeriesName}} | {{.Views}} | {{.Hours}} | {{.Span}} |
-{{- end }}
-
----
-
-## 5. データ品質・補足
-
-### メタデータ取得状況
-
-- 取得済み：**{{.CoveredViews}} / {{.TotalViews}}**（{{.CoverageRatio}}%）
-- 未取得（unresolved）：{{.UnresolvedCount}} 件
 
----
-
-### 注記
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/genre"
+	"github.com/kmdkuk/nfrecap/internal/i18n"
+)
 
-- 本文章は [https://github.com/kmdkuk/nfrecap](https://github.com/kmdkuk/nfrecap) を利用して生成されました。
-- 作品の特定およびメタデータ取得には [https://www.themoviedb.org/](https://www.themoviedb.org/) を利用しています。
-- Disclaimer: This nfrecap uses TMDB and the TMDB APIs but is not endorsed, certified, or otherwise approved by TMDB.
-- 推定視聴時間は参考値であり、実際の再生時間と一致しない場合があります。
-- 未取得作品は、今後の正規化ルール改善や手動補正で解消できる可能性があります。
-`
+// MarkdownOptions controls RenderMarkdownWithOptions.
+type MarkdownOptions struct {
+	// Mermaid adds Mermaid charts below the monthly, weekday, genre and
+	// series tables (see mermaid.go).
+	Mermaid bool
+}
 
-	data := prepareViewData(s)
+// RenderMarkdown renders the built-in Markdown report.
+func RenderMarkdown(s Stats) string {
+	return RenderMarkdownWithOptions(s, MarkdownOptions{})
+}
 
-	t, err := template.New("recap").Parse(tmplStr)
+// RenderMarkdownWithOptions renders the built-in Markdown report with opts.
+// A rendering error is written into the report; callers that need it use
+// RenderMarkdownTemplateWithOptions with BuiltinTemplate(MarkdownTemplateFile).
+func RenderMarkdownWithOptions(s Stats, opts MarkdownOptions) string {
+	out, err := RenderMarkdownTemplateWithOptions(s, opts, BuiltinTemplate(MarkdownTemplateFile))
 	if err != nil {
-		return fmt.Sprintf("Error parsing template: %v", err)
-	}
-
-	var buf bytes.Buffer
-	if err := t.Execute(&buf, data); err != nil {
-		return fmt.Sprintf("Error executing template: %v", err)
+		return fmt.Sprintf("Error rendering report: %v", err)
 	}
-
-	return buf.String()
+	return out
 }
 
-// view data structs
+// viewData is the data of every report template. Data is the versioned
+// export schema and the stable part for custom templates; the other fields
+// are preformatted for the built-in templates and may change between
+// releases.
 type viewData struct {
+	Data Export
+
+	Lang               string
 	Year               int
+	PeriodLabel        string
+	PeriodFrom         string
+	PeriodTo           string
+	PeriodDays         int
+	IsCalendarYear     bool
+	FilterLabel        string // empty unless the views were filtered
 	GeneratedAt        string
 	SourceFile         string
 	TotalDurationHours string
@@ -192,16 +66,82 @@ type viewData struct {
 	MaxGapDays         int
 	MaxGapStart        string
 	MaxGapEnd          string
+	CalendarSVG        string
+
+	Limits  Limits
+	SortBy  string
+	Show    map[string]bool // section -> enabled
+	Mermaid bool            // MarkdownOptions.Mermaid
 
+	WeekdayNames            []string // Sunday first
 	MonthlyRows             []monthlyRow
 	WeekdayRows             []weekdayRow
 	TopStreaksRows          []streakRow
 	GenreRows               []genreRow
 	GenreSpikeRows          []spikeRow
+	GenreTrendHeader        []string
+	GenreTrendRows          []genreTrendRow
 	TopTitlesByDurationRows []titleRow
 	TopTitlesByViewsRows    []titleRow
-	TopSeriesRows           []seriesRow
+	TitleRankings           []titleRanking  // the configured sort key first
+	SeriesRankings          []seriesRanking // the configured sort key first
 	UnresolvedRows          []unresolvedRow
+	UnresolvedAppendixRows  []unresolvedRow
+
+	FirstTimeViews int
+	RepeatViews    int
+	RepeatRatio    string
+	TopRewatchRows []rewatchRow
+
+	GenreEntropy        string
+	GenreEvenness       string
+	ConcentrationTitles int
+	TopTitlesShare      string
+	NewWorks            int
+	Works               int
+	DiscoveryRate       string
+	DiscoveryRows       []discoveryRow
+	OneAndDoneCount     int
+	OneAndDoneSeries    string
+
+	Comparison *comparisonView
+
+	BusiestWeek      string
+	BusiestWeekSpan  string
+	BusiestWeekHours string
+	WeekdayAvgMin    string
+	WeekendAvgMin    string
+	TopWeekRows      []weekRow
+	HolidayRows      []holidayRow
+	BaselineMin      string
+
+	TimedViews          int
+	HourRows            []hourRow
+	LateNightFrom       int
+	LateNightTo         int
+	LateNightViews      int
+	LateNightShare      string
+	SessionCount        int
+	SessionGapMin       int
+	AvgSessionMin       string
+	LongestSessionMin   int
+	LongestSessionStart string
+	LongestSessionEnd   string
+	LongestSessionViews int
+
+	DeviceRows []deviceRow
+
+	SeriesFinishedRows   []completionRow
+	SeriesInProgressRows []completionRow
+	SeriesAbandonedRows  []completionRow
+	AbandonAfterDays     int
+}
+
+type discoveryRow struct {
+	Month    string
+	Works    int
+	NewWorks int
+	Rate     string
 }
 
 type monthlyRow struct {
@@ -215,6 +155,34 @@ type weekdayRow struct {
 	Views   int
 	Hours   string
 }
+type weekRow struct {
+	Rank  int
+	Week  string
+	Span  string
+	Views int
+	Hours string
+}
+type holidayRow struct {
+	Name       string
+	Days       int
+	ActiveDays int
+	Hours      string
+	AvgMin     string
+	Ratio      string
+}
+type hourRow struct {
+	Hour  string
+	Hours []string // Sunday first
+}
+type deviceRow struct {
+	Name         string
+	Views        int
+	Hours        string
+	Share        string
+	WeekdayHours string
+	WeekendHours string
+	TopGenres    string
+}
 type streakRow struct {
 	Rank  int
 	Days  int
@@ -234,12 +202,25 @@ type spikeRow struct {
 	Hours string
 	Note  string
 }
+type genreTrendRow struct {
+	Month  string
+	Shares []string
+}
 type titleRow struct {
-	Rank  int
-	Title string
-	Type  string
-	Hours string
-	Views int
+	Rank       int
+	Title      string
+	Type       string
+	Hours      string
+	Views      int
+	PosterPath string
+}
+type titleRanking struct {
+	Label string // what the ranking is sorted by
+	Rows  []titleRow
+}
+type seriesRanking struct {
+	Label string
+	Rows  []seriesRow
 }
 type seriesRow struct {
 	Rank       int
@@ -247,17 +228,73 @@ type seriesRow struct {
 	Views      int
 	Hours      string
 	Span       string
+	PosterPath string
+}
+type comparisonView struct {
+	BaseLabel         string
+	BaseHours         string
+	BaseViews         int
+	BaseActiveDays    int
+	HoursDelta        string
+	HoursPct          string
+	ViewsDelta        string
+	ActiveDaysDelta   string
+	GenreShiftRows    []genreShiftRow
+	NewTopGenres      string
+	CarriedOverSeries string
+	MonthShiftRows    []monthShiftRow
+}
+type genreShiftRow struct {
+	Name      string
+	BaseShare string
+	Share     string
+	Delta     string
+}
+type monthShiftRow struct {
+	Month     string
+	BaseHours string
+	Hours     string
+	Delta     string
+}
+type rewatchRow struct {
+	Rank         int
+	Title        string
+	Type         string
+	Rewatches    int
+	Episodes     string
+	FirstWatched string
+}
+type completionRow struct {
+	SeriesName string
+	Episodes   string
+	Completion string
+	Date       string
 }
 type unresolvedRow struct {
-	Rank  int
-	Title string
-	Type  string
-	Views int
+	Rank      int
+	Title     string
+	Type      string
+	Views     int
+	RawTitles string
 }
 
 func prepareViewData(s Stats) viewData {
+	cfg := s.Config.withDefaults()
+	limits := cfg.Limits
+	p := s.printer()
 	vd := viewData{
+		Data:             NewExport(s),
+		Lang:             p.Lang(),
+		Limits:           limits,
+		SortBy:           cfg.SortBy,
+		Show:             make(map[string]bool),
 		Year:             s.Year,
+		PeriodLabel:      periodLabel(p, s.Period),
+		PeriodFrom:       p.Date(s.Period.From),
+		PeriodTo:         p.Date(s.Period.To),
+		PeriodDays:       s.PeriodDays,
+		IsCalendarYear:   s.Period.IsCalendarYear(),
+		FilterLabel:      filterLabel(p, s.Filter),
 		GeneratedAt:      s.GeneratedAt,
 		SourceFile:       s.SourceFile,
 		TotalDurationMin: s.TotalDurationMin,
@@ -266,51 +303,152 @@ func prepareViewData(s Stats) viewData {
 		UnresolvedCount:  s.UnresolvedCount,
 	}
 
-	vd.TotalDurationHours = fmt.Sprintf("%.1f", float64(s.TotalDurationMin)/60.0)
-	vd.ActiveRatio = fmt.Sprintf("%.1f", float64(s.ActiveDays)/365.0*100.0) // simplified 365
+	vd.TotalDurationHours = p.Hours(s.TotalDurationMin)
+	vd.ActiveRatio = p.Float(0, 1)
+	if s.PeriodDays > 0 {
+		vd.ActiveRatio = p.Float(float64(s.ActiveDays)/float64(s.PeriodDays)*100.0, 1)
+	}
 
 	covered := s.TotalViews - s.UnresolvedCount
 	vd.CoveredViews = covered
 	if s.TotalViews > 0 {
-		vd.CoverageRatio = fmt.Sprintf("%.1f", float64(covered)/float64(s.TotalViews)*100.0)
+		vd.CoverageRatio = p.Float(float64(covered)/float64(s.TotalViews)*100.0, 1)
 	} else {
-		vd.CoverageRatio = "0.0"
+		vd.CoverageRatio = p.Float(0, 1)
 	}
 
 	// Longest Streak
 	if len(s.TopStreaks) > 0 {
 		top := s.TopStreaks[0]
 		vd.LongestStreakDays = top.Days
-		vd.LongestStreakStart = top.Start.Format("2006-01-02")
-		vd.LongestStreakEnd = top.End.Format("2006-01-02")
+		vd.LongestStreakStart = p.Date(top.Start)
+		vd.LongestStreakEnd = p.Date(top.End)
 	}
 
 	// Max Gap
 	vd.MaxGapDays = s.MaxGap.Days
 	if s.MaxGap.Days > 0 {
-		vd.MaxGapStart = s.MaxGap.Start.Format("2006-01-02")
-		vd.MaxGapEnd = s.MaxGap.End.Format("2006-01-02")
+		vd.MaxGapStart = p.Date(s.MaxGap.Start)
+		vd.MaxGapEnd = p.Date(s.MaxGap.End)
+	}
+
+	for _, name := range Sections {
+		vd.Show[name] = cfg.Enabled(name)
 	}
 
+	vd.CalendarSVG = RenderCalendarSVG(s)
+
 	// Monthly
-	for m := time.January; m <= time.December; m++ {
-		metric := s.MonthlyStats[m]
+	singleYear := s.Period.From.Year() == s.Period.To.Year()
+	monthLabels := make([]string, len(s.MonthlySeries))
+	for i, metric := range s.MonthlySeries {
+		label := p.Month(metric.Month)
+		if !singleYear {
+			label = p.YearMonth(metric.Year, metric.Month)
+		}
+		monthLabels[i] = label
 		vd.MonthlyRows = append(vd.MonthlyRows, monthlyRow{
-			Month:   fmt.Sprintf("%d月", m),
+			Month:   label,
 			Views:   metric.Views,
-			Hours:   fmt.Sprintf("%.1f", float64(metric.DurationMin)/60.0),
+			Hours:   p.Hours(metric.DurationMin),
 			Minutes: metric.DurationMin,
 		})
 	}
 
 	// Weekday
-	weekdays := []string{"日", "月", "火", "水", "木", "金", "土"}
-	for i, w := range weekdays {
-		metric := s.WeekdayStats[time.Weekday(i)]
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		vd.WeekdayNames = append(vd.WeekdayNames, p.Weekday(wd))
+		metric := s.WeekdayStats[wd]
 		vd.WeekdayRows = append(vd.WeekdayRows, weekdayRow{
-			Weekday: w,
+			Weekday: p.Weekday(wd),
 			Views:   metric.Views,
-			Hours:   fmt.Sprintf("%.1f", float64(metric.DurationMin)/60.0),
+			Hours:   p.Hours(metric.DurationMin),
+		})
+	}
+
+	// Weeks
+	weekSpan := func(w WeekMetric) string {
+		return p.T("span", p.Date(w.Start), p.Date(w.Start.AddDate(0, 0, 6)))
+	}
+	vd.BusiestWeek = "-"
+	if s.BusiestWeek.DurationMin > 0 {
+		vd.BusiestWeek = s.BusiestWeek.Label()
+		vd.BusiestWeekSpan = weekSpan(s.BusiestWeek)
+	}
+	vd.BusiestWeekHours = p.Hours(s.BusiestWeek.DurationMin)
+	vd.WeekdayAvgMin = p.Float(s.DayTypeStats.WeekdayAvgMin, 0)
+	vd.WeekendAvgMin = p.Float(s.DayTypeStats.WeekendAvgMin, 0)
+
+	weeks := make([]WeekMetric, 0, len(s.WeeklyStats))
+	for _, w := range s.WeeklyStats {
+		if w.DurationMin > 0 {
+			weeks = append(weeks, w)
+		}
+	}
+	sort.SliceStable(weeks, func(i, j int) bool { return weeks[i].DurationMin > weeks[j].DurationMin })
+	for i, w := range weeks {
+		if i >= 5 {
+			break
+		}
+		vd.TopWeekRows = append(vd.TopWeekRows, weekRow{
+			Rank:  i + 1,
+			Week:  w.Label(),
+			Span:  weekSpan(w),
+			Views: w.Views,
+			Hours: p.Hours(w.DurationMin),
+		})
+	}
+
+	// Holidays
+	for _, h := range s.HolidayStats {
+		ratio := "-"
+		if h.RatioToUsual > 0 {
+			ratio = p.T("ratio", p.Float(h.RatioToUsual, 1))
+		}
+		vd.HolidayRows = append(vd.HolidayRows, holidayRow{
+			Name:       holidayLabel(p, h.Name),
+			Days:       h.Days,
+			ActiveDays: h.ActiveDays,
+			Hours:      p.Hours(h.DurationMin),
+			AvgMin:     p.Float(h.AvgMin, 0),
+			Ratio:      ratio,
+		})
+		vd.BaselineMin = p.Float(h.BaselineMin, 0)
+	}
+
+	// Time of day
+	vd.TimedViews = s.TimedViews
+	if s.TimedViews > 0 {
+		for h := 0; h < 24; h++ {
+			row := hourRow{Hour: p.T("hour", h)}
+			for wd := time.Sunday; wd <= time.Saturday; wd++ {
+				row.Hours = append(row.Hours, p.Hours(s.HourWeekdayHeatmap[wd][h].DurationMin))
+			}
+			vd.HourRows = append(vd.HourRows, row)
+		}
+		vd.LateNightFrom = lateNightStartHour
+		vd.LateNightTo = lateNightEndHour
+		vd.LateNightViews = s.LateNightViews
+		vd.LateNightShare = p.Float(s.LateNightShare, 1)
+		vd.SessionCount = s.SessionCount
+		vd.SessionGapMin = int(sessionGap.Minutes())
+		vd.AvgSessionMin = p.Float(s.AvgSessionMin, 0)
+		vd.LongestSessionMin = s.LongestSession.DurationMin
+		vd.LongestSessionStart = p.DateTime(s.LongestSession.Start)
+		vd.LongestSessionEnd = s.LongestSession.End.Format("15:04")
+		vd.LongestSessionViews = s.LongestSession.Views
+	}
+
+	// Devices
+	for _, d := range s.DeviceStats {
+		vd.DeviceRows = append(vd.DeviceRows, deviceRow{
+			Name:         deviceLabel(p, d.Class),
+			Views:        d.Views,
+			Hours:        p.Hours(d.DurationMin),
+			Share:        p.Float(d.Share, 1),
+			WeekdayHours: p.Hours(d.WeekdayMin),
+			WeekendHours: p.Hours(d.WeekendMin),
+			TopGenres:    strings.Join(d.TopGenres, ", "),
 		})
 	}
 
@@ -319,8 +457,8 @@ func prepareViewData(s Stats) viewData {
 		vd.TopStreaksRows = append(vd.TopStreaksRows, streakRow{
 			Rank:  i + 1,
 			Days:  st.Days,
-			Start: st.Start.Format("2006-01-02"),
-			End:   st.End.Format("2006-01-02"),
+			Start: p.Date(st.Start),
+			End:   p.Date(st.End),
 		})
 	}
 
@@ -329,13 +467,13 @@ func prepareViewData(s Stats) viewData {
 	otherGenreDur := 0
 	otherGenreViews := 0
 
-	limit := 10
+	limit := limits.Genres
 	usedMovies := make(map[string]bool) // Track movies already used in samples
 
 	for i, g := range s.GenreStats {
 		// Prepare samples string with deduplication
 		samples := ""
-		if movies, ok := s.GenreSampleMovies[g.Name]; ok && len(movies) > 0 {
+		if movies, ok := s.GenreSampleMovies[g.Key]; ok && len(movies) > 0 {
 			// Filter out already used movies
 			var availableMovies []string
 			for _, movie := range movies {
@@ -344,8 +482,8 @@ func prepareViewData(s Stats) viewData {
 				}
 			}
 
-			// Take up to 3 unique movies
-			sampleLimit := 3
+			// Take up to the configured number of unique movies
+			sampleLimit := limits.GenreSamplesShown
 			if len(availableMovies) < sampleLimit {
 				sampleLimit = len(availableMovies)
 			}
@@ -364,8 +502,8 @@ func prepareViewData(s Stats) viewData {
 		if i < limit {
 			vd.GenreRows = append(vd.GenreRows, genreRow{
 				Name:         g.Name,
-				Hours:        fmt.Sprintf("%.1f", float64(g.DurationMin)/60.0),
-				Share:        fmt.Sprintf("%.1f", g.Share),
+				Hours:        p.Hours(g.DurationMin),
+				Share:        p.Float(g.Share, 1),
 				Views:        g.Views,
 				SampleMovies: samples,
 			})
@@ -380,26 +518,26 @@ func prepareViewData(s Stats) viewData {
 			share = float64(otherGenreDur) / float64(s.TotalDurationMin) * 100.0
 		}
 		vd.GenreRows = append(vd.GenreRows, genreRow{
-			Name:  "その他",
-			Hours: fmt.Sprintf("%.1f", float64(otherGenreDur)/60.0),
-			Share: fmt.Sprintf("%.1f", share),
+			Name:  p.T("other"),
+			Hours: p.Hours(otherGenreDur),
+			Share: p.Float(share, 1),
 			Views: otherGenreViews,
 		})
 	}
 
 	// Spikes
 	// Just show top genres spikes? Or all? User said "Spike1, ..." table.
-	// Let's show spikes for Top 10 genres.
+	// Let's show spikes for the top genres.
 	for i, g := range s.GenreStats {
-		if i >= 10 {
+		if i >= limits.Genres {
 			break
 		}
-		sp, ok := s.GenreMonthSpike[g.Name]
+		sp, ok := s.GenreMonthSpike[g.Key]
 		if ok {
 			vd.GenreSpikeRows = append(vd.GenreSpikeRows, spikeRow{
 				Name:  g.Name,
 				Month: int(sp.Month),
-				Hours: fmt.Sprintf("%.1f", float64(sp.DurationMin)/60.0),
+				Hours: p.Hours(sp.DurationMin),
 				Note:  "", // Placeholder
 			})
 		}
@@ -410,63 +548,295 @@ func prepareViewData(s Stats) viewData {
 		return vd.GenreSpikeRows[i].Month < vd.GenreSpikeRows[j].Month
 	})
 
+	// Genre trends: months as rows, top genres as columns
+	trends := s.GenreTrends
+	if len(trends) > genreTrendColumns {
+		trends = trends[:genreTrendColumns]
+	}
+	if len(trends) > 0 {
+		for _, t := range trends {
+			vd.GenreTrendHeader = append(vd.GenreTrendHeader, t.Name)
+		}
+		for i, label := range monthLabels {
+			row := genreTrendRow{Month: label}
+			for _, t := range trends {
+				row.Shares = append(row.Shares, p.Float(t.Months[i].Share, 1))
+			}
+			vd.GenreTrendRows = append(vd.GenreTrendRows, row)
+		}
+	}
+
 	// Titles
 	for i, t := range s.TopTitlesByDurationRows(s.TopTitlesByDuration) {
 		vd.TopTitlesByDurationRows = append(vd.TopTitlesByDurationRows, titleRow{
-			Rank:  i + 1,
-			Title: t.Title,
-			Type:  t.Type,
-			Hours: fmt.Sprintf("%.1f", float64(t.DurationMin)/60.0),
-			Views: t.Views,
+			Rank:       i + 1,
+			Title:      t.Title,
+			Type:       t.Type,
+			Hours:      p.Hours(t.DurationMin),
+			Views:      t.Views,
+			PosterPath: t.PosterPath,
 		})
 	}
 	for i, t := range s.TopTitlesByViewsRows(s.TopTitlesByViews) {
 		vd.TopTitlesByViewsRows = append(vd.TopTitlesByViewsRows, titleRow{
-			Rank:  i + 1,
-			Title: t.Title,
-			Type:  t.Type,
-			Hours: fmt.Sprintf("%.1f", float64(t.DurationMin)/60.0),
-			Views: t.Views,
+			Rank:       i + 1,
+			Title:      t.Title,
+			Type:       t.Type,
+			Hours:      p.Hours(t.DurationMin),
+			Views:      t.Views,
+			PosterPath: t.PosterPath,
 		})
 	}
 
+	byDuration := titleRanking{Label: p.T("sort.duration"), Rows: vd.TopTitlesByDurationRows}
+	byViews := titleRanking{Label: p.T("sort.views"), Rows: vd.TopTitlesByViewsRows}
+	vd.TitleRankings = []titleRanking{byDuration, byViews}
+
 	// Series
-	for i, ser := range s.TopSeriesByDuration {
-		if i >= 10 {
+	seriesByDuration := seriesRanking{Label: p.T("sort.duration"), Rows: newSeriesRows(p, s.TopSeriesByDuration, limits.TitlesShown)}
+	seriesByViews := seriesRanking{Label: p.T("sort.views"), Rows: newSeriesRows(p, s.TopSeriesByViews, limits.TitlesShown)}
+	vd.SeriesRankings = []seriesRanking{seriesByDuration, seriesByViews}
+
+	if cfg.SortBy == SortByViews {
+		vd.TitleRankings = []titleRanking{byViews, byDuration}
+		vd.SeriesRankings = []seriesRanking{seriesByViews, seriesByDuration}
+	}
+
+	// Series completion
+	vd.AbandonAfterDays = abandonAfterDays
+	for _, sp := range s.SeriesFinished {
+		vd.SeriesFinishedRows = append(vd.SeriesFinishedRows, newCompletionRow(p, sp, sp.CompletedAt))
+	}
+	for _, sp := range s.SeriesInProgress {
+		vd.SeriesInProgressRows = append(vd.SeriesInProgressRows, newCompletionRow(p, sp, sp.LastWatched))
+	}
+	for _, sp := range s.SeriesAbandoned {
+		vd.SeriesAbandonedRows = append(vd.SeriesAbandonedRows, newCompletionRow(p, sp, sp.LastWatched))
+	}
+
+	// Comparison
+	if c := s.Comparison; c != nil {
+		cv := &comparisonView{
+			BaseLabel:         periodLabel(p, c.BasePeriod),
+			BaseHours:         p.Hours(c.BaseTotalDurationMin),
+			BaseViews:         c.BaseTotalViews,
+			BaseActiveDays:    c.BaseActiveDays,
+			HoursDelta:        p.Signed(float64(c.TotalDurationMinDelta)/60.0, 1),
+			HoursPct:          p.Signed(c.TotalDurationPct, 1),
+			ViewsDelta:        p.Signed(float64(c.TotalViewsDelta), 0),
+			ActiveDaysDelta:   p.Signed(float64(c.ActiveDaysDelta), 0),
+			NewTopGenres:      strings.Join(c.NewTopGenres, ", "),
+			CarriedOverSeries: strings.Join(c.CarriedOverSeries, ", "),
+		}
+		for i, g := range c.GenreShifts {
+			if i >= 10 {
+				break
+			}
+			cv.GenreShiftRows = append(cv.GenreShiftRows, genreShiftRow{
+				Name:      g.Name,
+				BaseShare: p.Float(g.BaseShare, 1),
+				Share:     p.Float(g.Share, 1),
+				Delta:     p.Signed(g.DeltaPt, 1),
+			})
+		}
+		for i, m := range c.MonthShifts {
+			if i >= 3 {
+				break
+			}
+			cv.MonthShiftRows = append(cv.MonthShiftRows, monthShiftRow{
+				Month:     p.Month(m.Month),
+				BaseHours: p.Hours(m.BaseDurationMin),
+				Hours:     p.Hours(m.DurationMin),
+				Delta:     p.Signed(float64(m.DeltaMin)/60.0, 1),
+			})
+		}
+		vd.Comparison = cv
+	}
+
+	// Rewatches
+	vd.FirstTimeViews = s.FirstTimeViews
+	vd.RepeatViews = s.RepeatViews
+	vd.RepeatRatio = p.Float(0, 1)
+	if s.TotalViews > 0 {
+		vd.RepeatRatio = p.Float(float64(s.RepeatViews)/float64(s.TotalViews)*100.0, 1)
+	}
+	for i, r := range s.TopRewatches {
+		if i >= limits.TitlesShown {
 			break
 		}
-		vd.TopSeriesRows = append(vd.TopSeriesRows, seriesRow{
-			Rank:       i + 1,
-			SeriesName: ser.SeriesName,
-			Views:      ser.Views,
-			Hours:      fmt.Sprintf("%.1f", float64(ser.DurationMin)/60.0),
-			Span:       fmt.Sprintf("%s 〜 %s", ser.SpanStart.Format("2006-01-02"), ser.SpanEnd.Format("2006-01-02")),
+		episodes := "-"
+		if r.Type == "tv" {
+			episodes = p.Int(r.Episodes)
+		}
+		vd.TopRewatchRows = append(vd.TopRewatchRows, rewatchRow{
+			Rank:         i + 1,
+			Title:        r.Title,
+			Type:         r.Type,
+			Rewatches:    r.Rewatches,
+			Episodes:     episodes,
+			FirstWatched: p.Date(r.FirstWatched),
 		})
 	}
 
+	// Diversity & habits
+	h := s.Habits
+	vd.GenreEntropy = p.Float(h.GenreEntropy, 2)
+	vd.GenreEvenness = p.Float(h.GenreEvenness*100, 0)
+	vd.ConcentrationTitles = concentrationTitles
+	vd.TopTitlesShare = p.Float(h.TopTitlesShare, 1)
+	vd.NewWorks = h.NewWorks
+	vd.Works = h.Works
+	vd.DiscoveryRate = p.Float(h.DiscoveryRate, 1)
+	for i, dm := range h.Discovery {
+		vd.DiscoveryRows = append(vd.DiscoveryRows, discoveryRow{
+			Month:    monthLabels[i],
+			Works:    dm.Works,
+			NewWorks: dm.NewWorks,
+			Rate:     p.Float(dm.Rate, 1),
+		})
+	}
+	vd.OneAndDoneCount = len(h.OneAndDoneSeries)
+	vd.OneAndDoneSeries = strings.Join(h.OneAndDoneSeries, ", ")
+
 	// Unresolved
 	for i, u := range s.UnresolvedList {
-		vd.UnresolvedRows = append(vd.UnresolvedRows, unresolvedRow{
-			Rank:  i + 1,
-			Title: u.Title,
-			Type:  u.Type,
-			Views: u.Views,
-		})
+		row := unresolvedRow{
+			Rank:      i + 1,
+			Title:     u.Title,
+			Type:      u.Type,
+			Views:     u.Views,
+			RawTitles: strings.Join(u.RawTitles, " / "),
+		}
+		if i < limits.Unresolved {
+			vd.UnresolvedRows = append(vd.UnresolvedRows, row)
+		}
+		vd.UnresolvedAppendixRows = append(vd.UnresolvedAppendixRows, row)
 	}
 
 	return vd
 }
 
+func newSeriesRows(p *i18n.Printer, ss []SeriesStat, limit int) []seriesRow {
+	var rows []seriesRow
+	for i, ser := range ss {
+		if i >= limit {
+			break
+		}
+		rows = append(rows, seriesRow{
+			Rank:       i + 1,
+			SeriesName: ser.SeriesName,
+			Views:      ser.Views,
+			Hours:      p.Hours(ser.DurationMin),
+			Span:       p.T("span", p.Date(ser.SpanStart), p.Date(ser.SpanEnd)),
+			PosterPath: ser.PosterPath,
+		})
+	}
+	return rows
+}
+
+// periodLabel is Period.Label with localized dates for custom ranges.
+func periodLabel(p *i18n.Printer, period Period) string {
+	if period.IsCalendarYear() {
+		return period.Label()
+	}
+	return p.T("span", p.Date(period.From), p.Date(period.To))
+}
+
+// filterLabel describes the views a filtered recap covers, e.g. "TV /
+// genre Animation / 3 titles excluded", or "" without a filter.
+func filterLabel(p *i18n.Printer, f build.Filter) string {
+	var parts []string
+	if !f.From.IsZero() || !f.To.IsZero() {
+		from, to := "", ""
+		if !f.From.IsZero() {
+			from = p.Date(f.From)
+		}
+		if !f.To.IsZero() {
+			to = p.Date(f.To)
+		}
+		parts = append(parts, p.T("span", from, to))
+	}
+	if len(f.Types) > 0 {
+		types := make([]string, len(f.Types))
+		for i, t := range f.Types {
+			types[i] = p.T("filter.type_" + t)
+		}
+		parts = append(parts, strings.Join(types, ", "))
+	}
+	if len(f.Genres) > 0 {
+		var names []string
+		for _, name := range f.Genres {
+			for _, k := range genre.FromName(name) {
+				if n := genre.Name(k, p.Lang()); !slices.Contains(names, n) {
+					names = append(names, n)
+				}
+			}
+		}
+		parts = append(parts, p.T("filter.genres", strings.Join(names, ", ")))
+	}
+	if f.Title != "" {
+		parts = append(parts, p.T("filter.title", f.Title))
+	}
+	if f.TitleExpr != nil {
+		parts = append(parts, p.T("filter.title_regex", f.TitleExpr.String()))
+	}
+	if len(f.Providers) > 0 {
+		parts = append(parts, p.T("filter.providers", strings.Join(f.Providers, ", ")))
+	}
+	if f.Status != "" {
+		parts = append(parts, p.T("filter."+f.Status))
+	}
+	if n := len(f.ExcludeTitles); n > 0 {
+		parts = append(parts, p.T("filter.excluded", n))
+	}
+	return strings.Join(parts, " / ")
+}
+
+// deviceLabel returns the display name of a built-in device class.
+// Classes added via configuration are shown as-is.
+func deviceLabel(p *i18n.Printer, class string) string {
+	switch class {
+	case DeviceTV, DevicePhone, DeviceTablet, DeviceBrowser, DeviceOther, DeviceUnknown:
+		return p.T("device." + class)
+	}
+	return class
+}
+
+// holidayKeys are the message keys of the DefaultHolidays names.
+var holidayKeys = map[string]string{
+	"ゴールデンウィーク": "holiday.golden_week",
+	"お盆":        "holiday.obon",
+	"年末年始":      "holiday.new_year",
+}
+
+// holidayLabel returns the display name of a built-in holiday. Holidays
+// from a calendar file are shown as-is.
+func holidayLabel(p *i18n.Printer, name string) string {
+	if key, ok := holidayKeys[name]; ok {
+		return p.T(key)
+	}
+	return name
+}
+
+func newCompletionRow(p *i18n.Printer, sp SeriesProgress, date time.Time) completionRow {
+	return completionRow{
+		SeriesName: sp.SeriesName,
+		Episodes:   fmt.Sprintf("%s / %s", p.Int(sp.WatchedEpisodes), p.Int(sp.TotalEpisodes)),
+		Completion: p.Float(sp.CompletionPct, 1),
+		Date:       p.Date(date),
+	}
+}
+
 // Helpers for slice conversion
 func (s *Stats) TopTitlesByDurationRows(ts []TitleStat) []TitleStat {
-	if len(ts) > 10 {
-		return ts[:10]
-	}
-	return ts
+	return s.shownTitles(ts)
 }
 func (s *Stats) TopTitlesByViewsRows(ts []TitleStat) []TitleStat {
-	if len(ts) > 10 {
-		return ts[:10]
+	return s.shownTitles(ts)
+}
+func (s *Stats) shownTitles(ts []TitleStat) []TitleStat {
+	if limit := s.Config.withDefaults().Limits.TitlesShown; len(ts) > limit {
+		return ts[:limit]
 	}
 	return ts
 }
@@ -0,0 +1,140 @@
+package recap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/kmdkuk/nfrecap/internal/title"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMarkdownRankingsAndUnresolved(t *testing.T) {
+	resolved := func(date, raw string, runtime int) build.BuiltItem {
+		return build.BuiltItem{Date: date, Normalized: title.Normalize(raw), Metadata: &model.Metadata{Runtime: runtime}}
+	}
+	unresolved := func(date, raw string) build.BuiltItem {
+		return build.BuiltItem{Date: date, Normalized: title.Normalize(raw)}
+	}
+	items := []build.BuiltItem{
+		resolved("2024-01-01", "Epic", 180),
+		resolved("2024-01-02", "Short Show: シーズン1: 第1話", 10),
+		resolved("2024-01-03", "Short Show: シーズン1: 第2話", 10),
+		resolved("2024-01-04", "Short Show: シーズン1: 第3話", 10),
+		resolved("2024-01-05", "Long Show: シーズン1: 第1話", 60),
+		resolved("2024-01-06", "Long Show: シーズン1: 第2話", 60),
+		unresolved("2024-02-01", "Mystery Box: シーズン1: 第1話"),
+		unresolved("2024-02-02", "Mystery Box: シーズン2: 第1話"),
+		unresolved("2024-02-03", "Lost Film"),
+	}
+
+	s := ComputeStats(build.Built{Items: items}, 2024)
+	require.Len(t, s.UnresolvedList, 2)
+	assert.Equal(t, UnresolvedItem{
+		Title:     "Mystery Box",
+		Type:      "tv",
+		Views:     2,
+		RawTitles: []string{"Mystery Box: シーズン1: 第1話", "Mystery Box: シーズン2: 第1話"},
+	}, s.UnresolvedList[0])
+
+	md := RenderMarkdown(s)
+
+	// both rankings, duration first by default
+	byDuration := strings.Index(md, "### 推定視聴時間が多い作品（Top 5）")
+	byViews := strings.Index(md, "### 視聴回数が多い作品（Top 5）")
+	require.NotEqual(t, -1, byDuration)
+	require.NotEqual(t, -1, byViews)
+	assert.Less(t, byDuration, byViews)
+	assert.Contains(t, md, "### 推定視聴時間が多いシリーズ（TV作品のみ）")
+	assert.Contains(t, md, "### 視聴回数が多いシリーズ（TV作品のみ）")
+	views := md[strings.Index(md, "### 視聴回数が多いシリーズ"):]
+	assert.Contains(t, views, "| 1位 | Short Show | 3 | 0.5 |")
+
+	assert.Contains(t, md, "### 未取得の作品（視聴回数順）")
+	assert.Contains(t, md, "| 1位 | Mystery Box | tv | 2 |")
+	assert.Contains(t, md, "| 2位 | Lost Film | movie | 1 |")
+	assert.NotContains(t, md, "## 付録：未取得作品の一覧")
+
+	s = ComputeStatsWithOptions(build.Built{Items: items}, Options{
+		Period: YearPeriod(2024),
+		Config: Config{
+			Limits:   Limits{Unresolved: 1},
+			SortBy:   SortByViews,
+			Sections: map[string]bool{SectionUnresolvedAppendix: true},
+		},
+	})
+	assert.Len(t, s.UnresolvedList, 2) // the appendix keeps every work
+
+	md = RenderMarkdown(s)
+	assert.Less(t, strings.Index(md, "### 視聴回数が多い作品"), strings.Index(md, "### 推定視聴時間が多い作品"))
+	assert.NotContains(t, md, "| 2位 | Lost Film | movie | 1 |")
+	assert.Contains(t, md, "## 付録：未取得作品の一覧")
+	assert.Contains(t, md, "| Mystery Box | tv | 2 | Mystery Box: シーズン1: 第1話 / Mystery Box: シーズン2: 第1話 |")
+	assert.Contains(t, md, "| Lost Film | movie | 1 | Lost Film |")
+}
+
+func TestRenderMarkdownEscapesCells(t *testing.T) {
+	items := []build.BuiltItem{
+		{Date: "2024-01-01", Normalized: model.NormalizedTitle{RawTitle: "Yes | No", WorkTitle: "Yes | No", Type: "movie"}},
+		{Date: "2024-01-02", Normalized: model.NormalizedTitle{RawTitle: "Two\nLines", WorkTitle: "Two\nLines", Type: "movie"}},
+	}
+	s := ComputeStatsWithOptions(build.Built{Items: items}, Options{
+		Period: YearPeriod(2024),
+		Config: Config{Sections: map[string]bool{SectionUnresolvedAppendix: true}},
+	})
+
+	md := RenderMarkdown(s)
+	assert.Contains(t, md, "| Yes \\| No | movie | 1 | Yes \\| No |")
+	assert.Contains(t, md, "| Two Lines | movie | 1 | Two Lines |")
+	assert.NotContains(t, md, "Two\nLines")
+}
+
+func TestRenderMarkdownEnglish(t *testing.T) {
+	items := []build.BuiltItem{
+		{Date: "2024-01-01", Normalized: title.Normalize("Epic"), Metadata: &model.Metadata{Runtime: 120, Genres: []string{"Drama"}}},
+		{Date: "2024-03-02", Normalized: title.Normalize("Lost Film")},
+	}
+	s := ComputeStatsWithOptions(build.Built{Items: items}, Options{
+		Period: Period{From: YearPeriod(2024).From, To: YearPeriod(2024).From.AddDate(0, 5, 0)},
+		Config: Config{Lang: "en"},
+	})
+
+	md := RenderMarkdown(s)
+	assert.Contains(t, md, "# Netflix Recap Jan 1, 2024 – Jun 1, 2024")
+	assert.Contains(t, md, "- Estimated watch time: **2.0 hours** (120 min)")
+	assert.Contains(t, md, "| Jan | 1 | 2.0 |")
+	assert.Contains(t, md, "| Sun |")
+	assert.Contains(t, md, "| Drama | 2.0 | 100.0% | 1 |")
+	assert.Contains(t, md, "| #1 | Epic | movie | 2.0 | 1 |")
+	assert.Contains(t, md, "<title>Jan 1, 2024: 1 views / 2.0 hours</title>")
+	assert.NotContains(t, md, "位")
+	assert.NotContains(t, md, "月")
+
+	html := RenderHTML(s, HTMLOptions{})
+	assert.Contains(t, html, `<html lang="en">`)
+	assert.Contains(t, html, "<h2>At a Glance</h2>")
+}
+
+func TestRenderMarkdownFilterHeader(t *testing.T) {
+	built := build.Built{Items: []build.BuiltItem{
+		{Date: "2024-01-01", Normalized: title.Normalize("Show: シーズン1: 第1話"), Metadata: &model.Metadata{Runtime: 30, Genres: []string{"Animation"}}},
+		{Date: "2024-01-02", Normalized: title.Normalize("Kids Show: シーズン1: 第1話"), Metadata: &model.Metadata{Runtime: 30, Genres: []string{"Animation"}}},
+		{Date: "2024-01-03", Normalized: title.Normalize("Film"), Metadata: &model.Metadata{Runtime: 100}},
+	}}
+	opts := Options{
+		Period: YearPeriod(2024),
+		Config: Config{Lang: "en"},
+		Filter: build.Filter{Types: []string{"tv"}, Genres: []string{"アニメーション"}, ExcludeTitles: []string{"kids show"}},
+	}
+	md := RenderMarkdown(ComputeStatsWithOptions(built, opts))
+	assert.Contains(t, md, "> Filter: TV / Genre Animation / Excluded titles: 1\n")
+	assert.Contains(t, md, "Views: **1**")
+
+	opts.Config.Lang = "ja"
+	md = RenderMarkdown(ComputeStatsWithOptions(built, opts))
+	assert.Contains(t, md, "> 絞り込み: TV / ジャンル アニメーション / 除外タイトル 1 件\n")
+
+	assert.NotContains(t, RenderMarkdown(ComputeStats(built, 2024)), "絞り込み")
+}
@@ -0,0 +1,254 @@
+package recap
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	calCell   = 11 // cell size in px
+	calGap    = 2
+	calLeft   = 24 // room for weekday labels
+	calTop    = 16 // room for month labels
+	calLevels = 5
+)
+
+// calColors goes from "no views" to the busiest days.
+var calColors = [calLevels]string{"#ebedf0", "#ffc7c2", "#ff7a6e", "#e50914", "#8c0a0e"}
+
+// RenderCalendarSVG draws a GitHub-style calendar heatmap of s.Daily:
+// one column per week, one row per weekday (Sunday first), shaded by minutes.
+func RenderCalendarSVG(s Stats) string {
+	if len(s.Daily) == 0 {
+		return ""
+	}
+	thresholds := calThresholds(s.Daily)
+
+	offset := int(s.Daily[0].Date.Weekday())
+	weeks := (offset+len(s.Daily)-1)/7 + 1
+	step := calCell + calGap
+	width := calLeft + weeks*step
+	height := calTop + 7*step
+
+	p := s.printer()
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="9" fill="#767676">`,
+		width, height, width, height)
+	b.WriteString("\n")
+
+	for _, wd := range []time.Weekday{time.Monday, time.Wednesday, time.Friday} {
+		fmt.Fprintf(&b, `<text x="0" y="%d">%s</text>`+"\n", calTop+int(wd)*step+calCell-2, html.EscapeString(p.Weekday(wd)))
+	}
+
+	for i, d := range s.Daily {
+		pos := offset + i
+		x := calLeft + (pos/7)*step
+		y := calTop + (pos%7)*step
+		if d.Date.Day() == 1 || i == 0 {
+			fmt.Fprintf(&b, `<text x="%d" y="%d">%s</text>`+"\n", x, calTop-5, html.EscapeString(p.Month(d.Date.Month())))
+		}
+		title := p.T("chart.day", p.Date(d.Date), d.Views, p.Hours(d.DurationMin))
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"><title>%s</title></rect>`+"\n",
+			x, y, calCell, calCell, calColors[calLevel(d, thresholds)], html.EscapeString(title))
+	}
+
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// calThresholds splits the minutes of active days into quartiles, so the
+// shading adapts to how much someone watches.
+func calThresholds(days []DayMetric) []int {
+	var mins []int
+	for _, d := range days {
+		if d.Views > 0 {
+			mins = append(mins, d.DurationMin)
+		}
+	}
+	if len(mins) == 0 {
+		return nil
+	}
+	sort.Ints(mins)
+	th := make([]int, calLevels-2)
+	for i := range th {
+		th[i] = mins[len(mins)*(i+1)/(calLevels-1)]
+	}
+	return th
+}
+
+func calLevel(d DayMetric, thresholds []int) int {
+	if d.Views == 0 {
+		return 0
+	}
+	level := 1
+	for _, t := range thresholds {
+		if d.DurationMin >= t {
+			level++
+		}
+	}
+	return min(level, calLevels-1)
+}
+
+const (
+	chartWidth  = 640
+	chartHeight = 200
+	chartLeft   = 40 // room for the value axis
+	chartTop    = 12
+	chartBottom = 20 // room for category labels
+	chartColor  = "#e50914"
+
+	donutSize   = 200
+	donutRadius = 70
+	donutWidth  = 36
+	legendWidth = 220
+)
+
+// donutColors are used for the genre slices in order; "other" is grey.
+var donutColors = []string{
+	"#e50914", "#f5a623", "#4a90e2", "#7ed321", "#9013fe",
+	"#50e3c2", "#bd10e0", "#f8e71c", "#8b572a", "#417505",
+}
+
+const donutOtherColor = "#b3b3b3"
+
+type chartBar struct {
+	Label string
+	Value float64 // bar height
+	Title string  // tooltip
+}
+
+// RenderMonthlyBarSVG draws estimated hours per month of the period.
+func RenderMonthlyBarSVG(s Stats) string {
+	p := s.printer()
+	bars := make([]chartBar, len(s.MonthlySeries))
+	for i, m := range s.MonthlySeries {
+		bars[i] = chartBar{
+			Label: p.Month(m.Month),
+			Value: float64(m.DurationMin) / 60.0,
+			Title: p.T("chart.day", fmt.Sprintf("%d-%02d", m.Year, m.Month), m.Views, p.Hours(m.DurationMin)),
+		}
+	}
+	return barChartSVG(bars)
+}
+
+// RenderWeekdayBarSVG draws estimated hours per weekday, Sunday first.
+func RenderWeekdayBarSVG(s Stats) string {
+	if s.TotalViews == 0 {
+		return ""
+	}
+	p := s.printer()
+	var bars []chartBar
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		m := s.WeekdayStats[wd]
+		bars = append(bars, chartBar{
+			Label: p.Weekday(wd),
+			Value: float64(m.DurationMin) / 60.0,
+			Title: p.T("chart.weekday", p.Weekday(wd), m.Views, p.Hours(m.DurationMin)),
+		})
+	}
+	return barChartSVG(bars)
+}
+
+// barChartSVG draws a vertical bar chart with a labelled maximum.
+func barChartSVG(bars []chartBar) string {
+	if len(bars) == 0 {
+		return ""
+	}
+	maxV := 0.0
+	for _, b := range bars {
+		maxV = max(maxV, b.Value)
+	}
+	plotW := chartWidth - chartLeft
+	plotH := chartHeight - chartTop - chartBottom
+	slot := float64(plotW) / float64(len(bars))
+	barW := slot * 0.7
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="10" fill="#767676">`,
+		chartWidth, chartHeight, chartWidth, chartHeight)
+	b.WriteString("\n")
+
+	base := chartTop + plotH
+	fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#d0d0d0"/>`+"\n", chartLeft, base, chartWidth, base)
+	fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end">%.1f h</text>`+"\n", chartLeft-4, chartTop+4, maxV)
+	fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end">0</text>`+"\n", chartLeft-4, base)
+
+	for i, bar := range bars {
+		h := 0.0
+		if maxV > 0 {
+			h = bar.Value / maxV * float64(plotH)
+		}
+		x := float64(chartLeft) + float64(i)*slot + (slot-barW)/2
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s</title></rect>`+"\n",
+			x, float64(base)-h, barW, h, chartColor, html.EscapeString(bar.Title))
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="middle">%s</text>`+"\n",
+			x+barW/2, chartHeight-6, html.EscapeString(bar.Label))
+	}
+
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// RenderGenreDonutSVG draws the minutes per genre as a donut chart with a
+// legend: the top Limits.Genres genres and the rest as "other". Shares are
+// of the minutes summed over genres, as a view counts for each of its genres.
+func RenderGenreDonutSVG(s Stats) string {
+	limit := s.Config.withDefaults().Limits.Genres
+	p := s.printer()
+	total := 0
+	for _, g := range s.GenreStats {
+		total += g.DurationMin
+	}
+	if total == 0 {
+		return ""
+	}
+
+	type slice struct {
+		name  string
+		min   int
+		color string
+	}
+	var parts []slice
+	other := 0
+	for i, g := range s.GenreStats {
+		if i < limit {
+			parts = append(parts, slice{g.Name, g.DurationMin, donutColors[i%len(donutColors)]})
+		} else {
+			other += g.DurationMin
+		}
+	}
+	if other > 0 {
+		parts = append(parts, slice{p.T("other"), other, donutOtherColor})
+	}
+
+	width := donutSize + legendWidth
+	height := max(donutSize, 20+len(parts)*18)
+	c := donutSize / 2
+	circ := 2 * math.Pi * donutRadius
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11" fill="#333">`,
+		width, height, width, height)
+	b.WriteString("\n")
+
+	offset := 0.0
+	for i, sl := range parts {
+		share := float64(sl.min) / float64(total)
+		length := share * circ
+		title := p.T("chart.genre", sl.name, p.Hours(sl.min), p.Float(share*100, 1))
+		fmt.Fprintf(&b, `<circle cx="%d" cy="%d" r="%d" fill="none" stroke="%s" stroke-width="%d" stroke-dasharray="%.2f %.2f" stroke-dashoffset="%.2f" transform="rotate(-90 %d %d)"><title>%s</title></circle>`+"\n",
+			c, c, donutRadius, sl.color, donutWidth, length, circ-length, -offset, c, c, html.EscapeString(title))
+		offset += length
+
+		y := 20 + i*18
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="10" height="10" fill="%s"/>`+"\n", donutSize+10, y-9, sl.color)
+		fmt.Fprintf(&b, `<text x="%d" y="%d">%s %s%%</text>`+"\n", donutSize+26, y, html.EscapeString(sl.name), p.Float(share*100, 1))
+	}
+
+	b.WriteString("</svg>\n")
+	return b.String()
+}
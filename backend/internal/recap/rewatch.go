@@ -0,0 +1,109 @@
+package recap
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+)
+
+type RewatchStat struct {
+	Title        string
+	Type         string    // movie or tv
+	Rewatches    int       // repeat views within the period
+	Episodes     int       // distinct episodes rewatched (tv only)
+	FirstWatched time.Time // first view in the whole history
+}
+
+type rewatchWork struct {
+	title, typ string
+}
+
+// computeRewatches classifies every view within [from, to] as first-time or
+// repeat. A view is a repeat when the same movie, or the same episode of a
+// series, was already watched on an earlier day (possibly in an earlier
+// period). Views are counted one by one: every view on the day of the first
+// watch is first-time, so resuming later that day is no rewatch, and every
+// view on a later day is a repeat, even several on the same day.
+func (s *Stats) computeRewatches(items []build.BuiltItem, from, to time.Time) {
+	firstSeen := make(map[string]time.Time) // movie or episode key -> first day watched
+	for _, it := range items {
+		d, err := time.Parse("2006-01-02", it.Date)
+		if err != nil || d.After(to) {
+			continue
+		}
+		k := rewatchKey(it)
+		if f, ok := firstSeen[k]; !ok || d.Before(f) {
+			firstSeen[k] = d
+		}
+	}
+
+	works := make(map[rewatchWork]*RewatchStat)
+	episodes := make(map[rewatchWork]map[string]bool)
+	for _, it := range items {
+		d, err := time.Parse("2006-01-02", it.Date)
+		if err != nil || d.Before(from) || d.After(to) {
+			continue
+		}
+		k := rewatchKey(it)
+		if !d.After(firstSeen[k]) {
+			s.FirstTimeViews++
+			continue
+		}
+		s.RepeatViews++
+
+		w := rewatchWork{title: it.Normalized.WorkTitle, typ: it.Normalized.Type}
+		if _, ok := works[w]; !ok {
+			works[w] = &RewatchStat{Title: w.title, Type: w.typ}
+			episodes[w] = make(map[string]bool)
+		}
+		works[w].Rewatches++
+		if w.typ == "tv" {
+			episodes[w][k] = true
+		}
+	}
+
+	// earliest view of the work as a whole
+	for _, it := range items {
+		w := rewatchWork{title: it.Normalized.WorkTitle, typ: it.Normalized.Type}
+		st, ok := works[w]
+		if !ok {
+			continue
+		}
+		f, ok := firstSeen[rewatchKey(it)]
+		if !ok {
+			continue
+		}
+		if st.FirstWatched.IsZero() || f.Before(st.FirstWatched) {
+			st.FirstWatched = f
+		}
+	}
+
+	var rs []RewatchStat
+	for w, st := range works {
+		st.Episodes = len(episodes[w])
+		rs = append(rs, *st)
+	}
+	sort.Slice(rs, func(i, j int) bool {
+		if rs[i].Rewatches != rs[j].Rewatches {
+			return rs[i].Rewatches > rs[j].Rewatches
+		}
+		return rs[i].FirstWatched.Before(rs[j].FirstWatched)
+	})
+
+	limit := s.Config.Limits.Titles
+	if len(rs) > limit {
+		s.TopRewatches = rs[:limit]
+	} else {
+		s.TopRewatches = rs
+	}
+}
+
+// rewatchKey identifies a movie or a single episode of a series.
+func rewatchKey(it build.BuiltItem) string {
+	n := it.Normalized
+	if n.Type == "tv" {
+		return "tv|" + n.WorkTitle + "|" + episodeKey(it)
+	}
+	return n.Type + "|" + n.WorkTitle
+}
@@ -0,0 +1,52 @@
+package recap
+
+import (
+	"testing"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeRewatches(t *testing.T) {
+	movie := func(date, title string) build.BuiltItem {
+		return build.BuiltItem{Date: date, Normalized: model.NormalizedTitle{WorkTitle: title, Type: "movie"}}
+	}
+	episode := func(date, series string, episodeNo int) build.BuiltItem {
+		return build.BuiltItem{Date: date, Normalized: model.NormalizedTitle{
+			WorkTitle: series, Type: "tv", SeasonNumber: 1, EpisodeNumber: episodeNo,
+		}}
+	}
+
+	items := []build.BuiltItem{
+		movie("2022-06-01", "Comfort"), // first watch in an earlier year
+		movie("2023-01-10", "Comfort"),
+		movie("2023-05-10", "Comfort"),
+		movie("2023-03-01", "Once"),
+		movie("2023-03-02", "Twice"),
+		movie("2023-03-02", "Twice"), // same day does not count as a rewatch
+		episode("2023-04-01", "Show", 1),
+		episode("2023-04-01", "Show", 2),
+		episode("2023-08-01", "Show", 1),
+		episode("2023-08-01", "Show", 1), // each view on a later day is a repeat
+		episode("2023-08-02", "Show", 3), // new episode, not a rewatch
+		movie("2024-01-01", "Comfort"),   // outside the period
+	}
+
+	s := ComputeStats(build.Built{Items: items}, 2023)
+
+	assert.Equal(t, 10, s.TotalViews)
+	assert.Equal(t, 4, s.RepeatViews)
+	assert.Equal(t, 6, s.FirstTimeViews)
+
+	if assert.Len(t, s.TopRewatches, 2) {
+		assert.Equal(t, "Comfort", s.TopRewatches[0].Title)
+		assert.Equal(t, 2, s.TopRewatches[0].Rewatches)
+		assert.Equal(t, "2022-06-01", s.TopRewatches[0].FirstWatched.Format("2006-01-02"))
+
+		assert.Equal(t, "Show", s.TopRewatches[1].Title)
+		assert.Equal(t, 2, s.TopRewatches[1].Rewatches)
+		assert.Equal(t, 1, s.TopRewatches[1].Episodes)
+		assert.Equal(t, "2023-04-01", s.TopRewatches[1].FirstWatched.Format("2006-01-02"))
+	}
+}
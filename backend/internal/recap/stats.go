@@ -1,17 +1,19 @@
 package recap
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
+	"slices"
 	"sort"
 	"time"
 
 	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/genre"
 )
 
 type Stats struct {
-	Year int
+	Year       int // year of Period.From
+	Period     Period
+	PeriodDays int
 
 	// Metadata from Built
 	GeneratedAt string
@@ -27,25 +29,68 @@ type Stats struct {
 	MaxGap     Gap
 
 	// Monthly / Weekday
-	MonthlyStats map[time.Month]Metric
-	WeekdayStats map[time.Weekday]Metric
+	MonthlyStats  map[time.Month]Metric // by month of year; empty if the period spans more than 12 months
+	MonthlySeries []MonthMetric         // every month of the period in order
+	WeekdayStats  map[time.Weekday]Metric
+	Daily         []DayMetric `json:"-"` // every day of the period; see NewCompactCalendar for APIs
+
+	// Weeks / weekday vs weekend / holidays
+	WeeklyStats  []WeekMetric // ISO weeks in order, partial weeks at the ends
+	BusiestWeek  WeekMetric
+	DayTypeStats DayTypeStats
+	HolidayStats []HolidayStat
+
+	// Time of day & sessions (only for items with a start time)
+	TimedViews         int
+	HourWeekdayHeatmap [7][24]Metric // [weekday][hour]
+	LateNightViews     int
+	LateNightShare     float64 // percent of timed views
+	SessionCount       int
+	AvgSessionMin      float64
+	LongestSession     Session
+
+	// Devices (only when the account export recorded a device type)
+	DeviceStats []DeviceStat
 
 	// Genres
 	GenreStats        []GenreStat
-	GenreMonthSpike   map[string]Spike    // Genre -> Spike Info
-	GenreSampleMovies map[string][]string // Genre -> List of movie titles
+	GenreTrends       []GenreTrend        // genre × month matrix, ordered like GenreStats
+	GenreMonthSpike   map[string]Spike    // Genre key -> Spike Info
+	GenreSampleMovies map[string][]string // Genre key -> List of movie titles
 
 	// Titles
 	TopTitlesByDuration []TitleStat
 	TopTitlesByViews    []TitleStat
+	Titles              []TitleStat `json:"-"` // every title by duration; the top lists are capped at Limits.Titles
 
 	// TV Series
 	TopSeriesByDuration []SeriesStat
 	TopSeriesByViews    []SeriesStat
+	Series              []SeriesStat `json:"-"` // every series by duration
+
+	// Diversity & habits
+	Habits HabitStats
+
+	// Rewatches
+	FirstTimeViews int
+	RepeatViews    int
+	TopRewatches   []RewatchStat
+
+	// Series completion (needs episode counts from the provider)
+	SeriesFinished   []SeriesProgress
+	SeriesAbandoned  []SeriesProgress
+	SeriesInProgress []SeriesProgress
 
 	// Unresolved
 	UnresolvedCount int
 	UnresolvedList  []UnresolvedItem
+
+	// Comparison with another period, set by the caller via Compare
+	Comparison *StatsDiff
+
+	// Settings the stats were computed with, also used when rendering
+	Config Config
+	Filter build.Filter `json:"-"`
 }
 
 type Metric struct {
@@ -53,6 +98,12 @@ type Metric struct {
 	DurationMin int
 }
 
+type MonthMetric struct {
+	Year  int
+	Month time.Month
+	Metric
+}
+
 type Streak struct {
 	Days  int
 	Start time.Time
@@ -66,7 +117,8 @@ type Gap struct {
 }
 
 type GenreStat struct {
-	Name        string
+	Key         string // canonical genre key, see package genre
+	Name        string // display name
 	DurationMin int
 	Views       int
 	Share       float64
@@ -82,6 +134,8 @@ type TitleStat struct {
 	Type        string // movie or tv
 	DurationMin int
 	Views       int
+	PosterPath  string   // from metadata, empty if unresolved
+	Genres      []string // genre keys, see package genre
 }
 
 type SeriesStat struct {
@@ -90,51 +144,90 @@ type SeriesStat struct {
 	Views       int
 	SpanStart   time.Time
 	SpanEnd     time.Time
+	PosterPath  string
 }
 
 type UnresolvedItem struct {
-	Title string
-	Type  string
-	Views int
+	Title     string
+	Type      string
+	Views     int
+	RawTitles []string // titles as they appear in the viewing history, sorted
 }
 
+// ReadBuiltJSON reads a built JSON file; see build.ReadJSON.
 func ReadBuiltJSON(path string) (build.Built, error) {
-	b, err := os.ReadFile(path)
-	if err != nil {
-		return build.Built{}, err
-	}
-	var x build.Built
-	if err := json.Unmarshal(b, &x); err != nil {
-		return build.Built{}, fmt.Errorf("invalid built json: %w", err)
-	}
-	return x, nil
+	return build.ReadJSON(path)
+}
+
+type Options struct {
+	Period Period
+
+	// DeviceRules classifies device types; DefaultDeviceRules when nil.
+	DeviceRules []DeviceRule
+
+	// Holidays are compared against usual days; DefaultHolidays when nil.
+	Holidays []Holiday
+
+	// Config sets ranking sizes, sort keys and report sections.
+	Config Config
+
+	// Filter restricts the views before anything is counted, so every
+	// section covers the same subset. Its date range also limits the
+	// history before Period that rewatches and discoveries look at.
+	Filter build.Filter
 }
 
+// ComputeStats computes stats for a calendar year.
 func ComputeStats(built build.Built, year int) Stats {
+	return ComputeStatsWithOptions(built, Options{Period: YearPeriod(year)})
+}
+
+func ComputeStatsWithOptions(built build.Built, opts Options) Stats {
+	period := opts.Period
+	now := asOf(built.Items, period.To) // of the whole history, not just the filtered views
+	built = opts.Filter.Apply(built)
+	deviceRules := opts.DeviceRules
+	if deviceRules == nil {
+		deviceRules = DefaultDeviceRules
+	}
+	holidays := opts.Holidays
+	if holidays == nil {
+		holidays = DefaultHolidays
+	}
 	s := Stats{
-		Year:              year,
+		Year:              period.From.Year(),
+		Period:            period,
+		PeriodDays:        period.Days(),
 		GeneratedAt:       built.GeneratedAt,
 		SourceFile:        built.Source,
 		MonthlyStats:      make(map[time.Month]Metric),
 		WeekdayStats:      make(map[time.Weekday]Metric),
 		GenreSampleMovies: make(map[string][]string),
+		Config:            opts.Config.withDefaults(),
+		Filter:            opts.Filter,
 	}
 
 	// Internal aggregation maps
-	genreMap := make(map[string]*Metric)                 // Genre -> Metric
-	genreMonthMap := make(map[string]map[time.Month]int) // Genre -> Month -> Duration
-	titleMap := make(map[string]*TitleStat)              // "Title|Type" -> TitleStat
-	seriesMap := make(map[string]*SeriesStat)            // SeriesName -> SeriesStat
-	unresolvedMap := make(map[string]int)                // Title|Type -> count
+	genreMap := make(map[string]*Metric)                   // Genre key -> Metric
+	genreMonthMap := make(map[string]map[time.Time]Metric) // Genre key -> First day of month -> Metric
+	monthSeriesMap := make(map[time.Time]Metric)           // First day of month -> Metric
+	dayMap := make(map[time.Time]Metric)                   // Day -> Metric
+	titleMap := make(map[string]*TitleStat)                // "Title|Type" -> TitleStat
+	seriesMap := make(map[string]*SeriesStat)              // SeriesName -> SeriesStat
+	unresolvedMap := make(map[string]int)                  // Title|Type -> count
+	unresolvedRaw := make(map[string]map[string]bool)      // Title|Type -> raw titles
 
 	var dates []time.Time
+	var timed []timedView
+	devices := make(map[string]*deviceAgg) // Device class -> aggregation
+	hasDevice := false
 
 	for _, it := range built.Items {
 		d, err := time.Parse("2006-01-02", it.Date)
 		if err != nil {
 			continue
 		}
-		if d.Year() != year {
+		if !period.Contains(d) {
 			continue
 		}
 
@@ -143,29 +236,50 @@ func ComputeStats(built build.Built, year int) Stats {
 		s.TotalViews++
 
 		// Metadata handling
-		dur := 0
+		dur := it.Minutes()
 		var genres []string
 
 		if it.Metadata != nil {
-			dur = it.Metadata.Runtime
-			genres = it.Metadata.Genres
+			genres = genre.Keys(*it.Metadata)
 		} else {
 			// Unresolved
 			key := fmt.Sprintf("%s|%s", it.Normalized.WorkTitle, it.Normalized.Type)
 			unresolvedMap[key]++
+			if unresolvedRaw[key] == nil {
+				unresolvedRaw[key] = make(map[string]bool)
+			}
+			unresolvedRaw[key][it.Normalized.RawTitle] = true
 			s.UnresolvedCount++
 		}
 
+		if it.StartTime != "" {
+			if st, err := time.Parse(time.RFC3339, it.StartTime); err == nil {
+				timed = append(timed, timedView{start: st, min: dur})
+			}
+		}
+
 		s.TotalDurationMin += dur
 
+		// Device
+		if it.Device != "" {
+			hasDevice = true
+		}
+		addDeviceView(devices, ClassifyDevice(it.Device, deviceRules), d, dur, genres)
+
 		// Monthly & Weekday
 		m := d.Month()
 		wd := d.Weekday()
 
-		mm := s.MonthlyStats[m]
-		mm.Views++
-		mm.DurationMin += dur
-		s.MonthlyStats[m] = mm
+		dm := dayMap[d]
+		dm.Views++
+		dm.DurationMin += dur
+		dayMap[d] = dm
+
+		mk := time.Date(d.Year(), m, 1, 0, 0, 0, 0, time.UTC)
+		ms := monthSeriesMap[mk]
+		ms.Views++
+		ms.DurationMin += dur
+		monthSeriesMap[mk] = ms
 
 		wm := s.WeekdayStats[wd]
 		wm.Views++
@@ -176,11 +290,14 @@ func ComputeStats(built build.Built, year int) Stats {
 		for _, g := range genres {
 			if _, ok := genreMap[g]; !ok {
 				genreMap[g] = &Metric{}
-				genreMonthMap[g] = make(map[time.Month]int)
+				genreMonthMap[g] = make(map[time.Time]Metric)
 			}
 			genreMap[g].Views++
 			genreMap[g].DurationMin += dur
-			genreMonthMap[g][m] += dur
+			gm := genreMonthMap[g][mk]
+			gm.Views++
+			gm.DurationMin += dur
+			genreMonthMap[g][mk] = gm
 
 			// Collect Movie Samples
 			if it.Normalized.Type == "movie" {
@@ -192,7 +309,7 @@ func ComputeStats(built build.Built, year int) Stats {
 					}
 				}
 				if !exists {
-					if len(s.GenreSampleMovies[g]) < 5 {
+					if len(s.GenreSampleMovies[g]) < s.Config.Limits.GenreSamples {
 						s.GenreSampleMovies[g] = append(s.GenreSampleMovies[g], it.Normalized.WorkTitle)
 					}
 				}
@@ -209,6 +326,14 @@ func ComputeStats(built build.Built, year int) Stats {
 		}
 		titleMap[tKey].Views++
 		titleMap[tKey].DurationMin += dur
+		for _, g := range genres {
+			if !slices.Contains(titleMap[tKey].Genres, g) {
+				titleMap[tKey].Genres = append(titleMap[tKey].Genres, g)
+			}
+		}
+		if it.Metadata != nil && titleMap[tKey].PosterPath == "" {
+			titleMap[tKey].PosterPath = it.Metadata.PosterPath
+		}
 
 		// Series
 		if it.Normalized.Type == "tv" {
@@ -219,6 +344,9 @@ func ComputeStats(built build.Built, year int) Stats {
 			st := seriesMap[sn]
 			st.Views++
 			st.DurationMin += dur
+			if it.Metadata != nil && st.PosterPath == "" {
+				st.PosterPath = it.Metadata.PosterPath
+			}
 			if d.Before(st.SpanStart) {
 				st.SpanStart = d
 			}
@@ -235,22 +363,54 @@ func ComputeStats(built build.Built, year int) Stats {
 	}
 	s.ActiveDays = len(activeDaysMap)
 
+	s.computeDaily(dayMap)
+	s.computeWeekly(holidays)
+
+	for _, mk := range period.months() {
+		s.MonthlySeries = append(s.MonthlySeries, MonthMetric{
+			Year:   mk.Year(),
+			Month:  mk.Month(),
+			Metric: monthSeriesMap[mk],
+		})
+	}
+	if len(s.MonthlySeries) <= 12 { // a longer period would merge e.g. two Januaries
+		for _, ms := range s.MonthlySeries {
+			s.MonthlyStats[ms.Month] = ms.Metric
+		}
+	}
+
 	// Post-aggregation processing
 
 	// Streaks & Gaps
 	s.computeStreaksAndGaps(dates)
 
+	// Time of day & sessions
+	s.computeTimeOfDay(timed)
+
+	// Devices
+	if hasDevice {
+		s.computeDevices(devices)
+	}
+
 	// Genres
-	s.computeGenres(genreMap, genreMonthMap)
+	s.computeGenres(genreMap)
+	s.computeGenreTrends(genreMonthMap)
 
 	// Titles
 	s.computeTitles(titleMap)
 
 	// Series
 	s.computeSeries(seriesMap)
+	s.computeSeriesCompletion(built.Items, period.From, period.To, now)
+
+	// Rewatches
+	s.computeRewatches(built.Items, period.From, period.To)
+
+	// Diversity & habits
+	s.computeHabits(built.Items, period.From, period.To, now)
 
 	// Unresolved
-	s.computeUnresolved(unresolvedMap)
+	s.computeUnresolved(unresolvedMap, unresolvedRaw)
 
 	return s
 }
@@ -311,8 +471,8 @@ func (s *Stats) computeStreaksAndGaps(dates []time.Time) {
 	sort.Slice(streaks, func(i, j int) bool {
 		return streaks[i].Days > streaks[j].Days
 	})
-	if len(streaks) > 3 {
-		s.TopStreaks = streaks[:3]
+	if limit := s.Config.Limits.Streaks; len(streaks) > limit {
+		s.TopStreaks = streaks[:limit]
 	} else {
 		s.TopStreaks = streaks
 	}
@@ -325,20 +485,25 @@ func (s *Stats) computeStreaksAndGaps(dates []time.Time) {
 	}
 }
 
-func (s *Stats) computeGenres(m map[string]*Metric, mm map[string]map[time.Month]int) {
+func (s *Stats) computeGenres(m map[string]*Metric) {
 	var gs []GenreStat
-	for name, met := range m {
+	for key, met := range m {
 		gs = append(gs, GenreStat{
-			Name:        name,
+			Key:         key,
+			Name:        genre.Name(key, s.Config.Lang),
 			DurationMin: met.DurationMin,
 			Views:       met.Views,
 			Share:       0, // filled later
 		})
 	}
 
-	// Sort by Duration Desc
+	// Sort by duration, Desc, whatever the sort key of the rankings: the
+	// shares, comparisons and top genres are all about watch time
 	sort.Slice(gs, func(i, j int) bool {
-		return gs[i].DurationMin > gs[j].DurationMin
+		if gs[i].DurationMin != gs[j].DurationMin {
+			return gs[i].DurationMin > gs[j].DurationMin
+		}
+		return gs[i].Key < gs[j].Key
 	})
 
 	// Fill Share
@@ -349,22 +514,6 @@ func (s *Stats) computeGenres(m map[string]*Metric, mm map[string]map[time.Month
 	}
 
 	s.GenreStats = gs
-
-	// Compute Spikes
-	s.GenreMonthSpike = make(map[string]Spike)
-	for name, monthDur := range mm {
-		var maxM time.Month
-		var maxD int
-		for m, d := range monthDur {
-			if d > maxD {
-				maxD = d
-				maxM = m
-			}
-		}
-		if maxD > 0 {
-			s.GenreMonthSpike[name] = Spike{Month: maxM, DurationMin: maxD}
-		}
-	}
 }
 
 func (s *Stats) computeTitles(m map[string]*TitleStat) {
@@ -386,17 +535,13 @@ func (s *Stats) computeTitles(m map[string]*TitleStat) {
 	// But let's keep all or top 20. Let's start with all, and filter in render, OR stick to plan hint.
 	// Actually for "Top Titles", template implies just top few. Let's store enough (e.g. 50) and slice in render.
 	// Wait, Slice is destructive if I re-sort. Copying.
-
-	ts2 := make([]TitleStat, len(ts))
-	copy(ts2, ts)
+	s.Titles = ts
 
 	// By Views
-	sort.Slice(ts2, func(i, j int) bool {
-		return ts2[i].Views > ts2[j].Views
-	})
+	ts2 := titlesByViews(ts)
 
 	// actually let's just store top 50 strictly
-	limit := 50
+	limit := s.Config.Limits.Titles
 	if len(ts) > limit {
 		s.TopTitlesByDuration = ts[:limit]
 	} else {
@@ -419,8 +564,9 @@ func (s *Stats) computeSeries(m map[string]*SeriesStat) {
 	sort.Slice(ss, func(i, j int) bool {
 		return ss[i].DurationMin > ss[j].DurationMin
 	})
+	s.Series = ss
 
-	limit := 50
+	limit := s.Config.Limits.Titles
 	if len(ss) > limit {
 		s.TopSeriesByDuration = ss[:limit]
 	} else {
@@ -428,11 +574,7 @@ func (s *Stats) computeSeries(m map[string]*SeriesStat) {
 	}
 
 	// If need by views
-	ss2 := make([]SeriesStat, len(ss))
-	copy(ss2, ss)
-	sort.Slice(ss2, func(i, j int) bool {
-		return ss2[i].Views > ss2[j].Views
-	})
+	ss2 := seriesByViews(ss)
 	if len(ss2) > limit {
 		s.TopSeriesByViews = ss2[:limit]
 	} else {
@@ -440,7 +582,35 @@ func (s *Stats) computeSeries(m map[string]*SeriesStat) {
 	}
 }
 
-func (s *Stats) computeUnresolved(m map[string]int) {
+// RankedTitles returns every title of the period, ordered by the configured
+// sort key. The top lists hold only the first Limits.Titles of them.
+func (s Stats) RankedTitles() []TitleStat {
+	if s.Config.SortBy == SortByViews {
+		return titlesByViews(s.Titles)
+	}
+	return s.Titles
+}
+
+// titlesByViews returns a copy of ts, which is sorted by duration, sorted by
+// views; equal views stay by duration.
+func titlesByViews(ts []TitleStat) []TitleStat {
+	c := slices.Clone(ts)
+	sort.SliceStable(c, func(i, j int) bool {
+		return c[i].Views > c[j].Views
+	})
+	return c
+}
+
+// seriesByViews is titlesByViews for series.
+func seriesByViews(ss []SeriesStat) []SeriesStat {
+	c := slices.Clone(ss)
+	sort.SliceStable(c, func(i, j int) bool {
+		return c[i].Views > c[j].Views
+	})
+	return c
+}
+
+func (s *Stats) computeUnresolved(m map[string]int, raw map[string]map[string]bool) {
 	var us []UnresolvedItem
 	for k, count := range m {
 		// k is "Title|Type"
@@ -474,19 +644,30 @@ func (s *Stats) computeUnresolved(m map[string]int) {
 			typ = "unknown"
 		}
 
+		var raws []string
+		for r := range raw[k] {
+			raws = append(raws, r)
+		}
+		sort.Strings(raws)
+
 		us = append(us, UnresolvedItem{
-			Title: title,
-			Type:  typ,
-			Views: count,
+			Title:     title,
+			Type:      typ,
+			Views:     count,
+			RawTitles: raws,
 		})
 	}
 
 	sort.Slice(us, func(i, j int) bool {
-		return us[i].Views > us[j].Views
+		if us[i].Views != us[j].Views {
+			return us[i].Views > us[j].Views
+		}
+		return us[i].Title < us[j].Title
 	})
 
-	if len(us) > 30 {
-		s.UnresolvedList = us[:30]
+	// the appendix lists every unresolved work
+	if limit := s.Config.Limits.Unresolved; len(us) > limit && !s.Config.Enabled(SectionUnresolvedAppendix) {
+		s.UnresolvedList = us[:limit]
 	} else {
 		s.UnresolvedList = us
 	}
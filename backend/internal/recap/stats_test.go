@@ -2,10 +2,12 @@ package recap
 
 import (
 	"testing"
+	"time"
 
 	"github.com/kmdkuk/nfrecap/internal/build"
 	"github.com/kmdkuk/nfrecap/internal/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestComputeStats(t *testing.T) {
@@ -56,16 +58,16 @@ func TestComputeStats(t *testing.T) {
 				// Comedy: 1 view, 90 min
 				var action, comedy GenreStat
 				for _, g := range s.GenreStats {
-					if g.Name == "Action" {
+					if g.Key == "action" {
 						action = g
 					}
-					if g.Name == "Comedy" {
+					if g.Key == "comedy" {
 						comedy = g
 					}
 				}
-				assert.Equal(t, "Action", action.Name)
+				assert.Equal(t, "アクション", action.Name)
 				assert.Equal(t, 210, action.DurationMin)
-				assert.Equal(t, "Comedy", comedy.Name)
+				assert.Equal(t, "コメディ", comedy.Name)
 				assert.Equal(t, 90, comedy.DurationMin)
 			},
 		},
@@ -171,3 +173,26 @@ func TestComputeStreaksAndGaps_EdgeCases(t *testing.T) {
 		assert.Equal(t, 0, s.MaxGap.Days)
 	})
 }
+
+func TestComputeStatsWithFilter(t *testing.T) {
+	movie := &model.Metadata{Runtime: 100, Genres: []string{"Horror"}}
+	show := &model.Metadata{Runtime: 30, Genres: []string{"Animation"}}
+	built := build.Built{Items: []build.BuiltItem{
+		{Date: "2023-01-01", Normalized: model.NormalizedTitle{WorkTitle: "Movie", Type: "movie"}, Metadata: movie},
+		{Date: "2023-01-02", Normalized: model.NormalizedTitle{WorkTitle: "Show", Type: "tv", Season: "S1", EpisodeTitle: "E1"}, Metadata: show},
+		{Date: "2023-01-03", Normalized: model.NormalizedTitle{WorkTitle: "Show", Type: "tv", Season: "S1", EpisodeTitle: "E2"}, Metadata: show},
+	}}
+
+	s := ComputeStatsWithOptions(built, Options{Period: YearPeriod(2023), Filter: build.Filter{Types: []string{"tv"}}})
+
+	// every section sees only the TV views
+	assert.Equal(t, 2, s.TotalViews)
+	assert.Equal(t, 60, s.TotalDurationMin)
+	assert.Equal(t, 2, s.ActiveDays)
+	assert.Equal(t, 2, s.MonthlyStats[time.January].Views)
+	require.Len(t, s.GenreStats, 1)
+	assert.Equal(t, "animation", s.GenreStats[0].Key)
+	require.Len(t, s.TopTitlesByViews, 1)
+	assert.Equal(t, "Show", s.TopTitlesByViews[0].Title)
+	require.Len(t, s.TopSeriesByViews, 1)
+}
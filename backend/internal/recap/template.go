@@ -0,0 +1,232 @@
+package recap
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/kmdkuk/nfrecap/internal/i18n"
+)
+
+// The built-in reports are ordinary templates, embedded from templates/.
+// They are the best starting point for a custom --template.
+//
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+const (
+	MarkdownTemplateFile = "templates/recap.md.tmpl"
+	HTMLTemplateFile     = "templates/recap.html.tmpl"
+	allTimeTemplateFile  = "templates/alltime.md.tmpl"
+)
+
+// BuiltinTemplate returns the source of a built-in template, e.g.
+// MarkdownTemplateFile.
+func BuiltinTemplate(name string) string {
+	b, err := templateFS.ReadFile(name)
+	if err != nil {
+		panic(err) // embedded, cannot happen for the names above
+	}
+	return string(b)
+}
+
+// RenderMarkdownTemplate renders s with a text/template source. The template
+// gets the same data as the built-in report (see README "Custom Templates")
+// and the helpers of templateFuncs.
+func RenderMarkdownTemplate(s Stats, text string) (string, error) {
+	return RenderMarkdownTemplateWithOptions(s, MarkdownOptions{}, text)
+}
+
+// RenderMarkdownTemplateWithOptions is RenderMarkdownTemplate with opts, which
+// templates see as .Mermaid. Any template can draw Mermaid charts with the
+// `mermaid` helper.
+func RenderMarkdownTemplateWithOptions(s Stats, opts MarkdownOptions, text string) (string, error) {
+	funcs := template.FuncMap(templateFuncs(s.printer()))
+	funcs["svg"] = func(name string) (string, error) {
+		return renderChart(s, name)
+	}
+	funcs["mermaid"] = func(name string) (string, error) {
+		return renderMermaid(s, name)
+	}
+	t, err := template.New("recap").Funcs(funcs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+	data := prepareViewData(s)
+	data.Mermaid = opts.Mermaid
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// RenderHTMLTemplate is RenderMarkdownTemplate for html/template, with the
+// `poster` helper for images from opts.PosterDir.
+func RenderHTMLTemplate(s Stats, opts HTMLOptions, text string) (string, error) {
+	funcs := htmltemplate.FuncMap(templateFuncs(s.printer()))
+	// SVGs are built from escaped strings in render_svg.go.
+	funcs["svg"] = func(name string) (htmltemplate.HTML, error) {
+		svg, err := renderChart(s, name)
+		return htmltemplate.HTML(svg), err
+	}
+	funcs["poster"] = func(path string) htmltemplate.URL {
+		return posterDataURI(opts.PosterDir, path)
+	}
+	t, err := htmltemplate.New("recap").Funcs(funcs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, prepareViewData(s)); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// charts are the SVG charts available to templates by name.
+var charts = map[string]func(Stats) string{
+	"monthly":  RenderMonthlyBarSVG,
+	"weekday":  RenderWeekdayBarSVG,
+	"calendar": RenderCalendarSVG,
+	"genres":   RenderGenreDonutSVG,
+}
+
+func renderChart(s Stats, name string) (string, error) {
+	chart, ok := charts[name]
+	if !ok {
+		return "", fmt.Errorf("unknown chart %q", name)
+	}
+	return chart(s), nil
+}
+
+// printer returns the printer for the report language of s.
+func (s Stats) printer() *i18n.Printer {
+	return i18n.For(s.Config.Lang)
+}
+
+// templateFuncs are the helpers available to every report template, all
+// formatting in the language of p:
+//
+//	T KEY ARGS...      message KEY of the catalog, formatted with ARGS
+//	num N              a number with digit grouping, e.g. "1,234" in English
+//	hours MIN          minutes as hours with one decimal, e.g. "1.5"
+//	percent PART TOTAL PART/TOTAL in percent with one decimal, "0.0" if TOTAL is 0
+//	monthName MONTH    month name, e.g. "1月" or "Jan"; MONTH is 1-12 or "YYYY-MM"
+//	top N LIST         the first N elements of a list
+//	md TEXT            TEXT safe for a Markdown table cell: "|" escaped, line breaks as spaces
+func templateFuncs(p *i18n.Printer) map[string]any {
+	return map[string]any{
+		"T":   p.T,
+		"num": func(n any) (string, error) { return formatNumber(p, n) },
+		"hours": func(minutes any) (string, error) {
+			m, err := toFloat(minutes)
+			if err != nil {
+				return "", err
+			}
+			return p.Float(m/60.0, 1), nil
+		},
+		"percent": func(part, total any) (string, error) {
+			v, err := formatPercent(part, total)
+			if err != nil {
+				return "", err
+			}
+			return p.Float(v, 1), nil
+		},
+		"monthName": func(v any) (string, error) {
+			m, err := monthOf(v)
+			if err != nil {
+				return "", err
+			}
+			return p.Month(m), nil
+		},
+		"top": topN,
+		"md":  mdCell.Replace,
+	}
+}
+
+// mdCell escapes titles and other user data for Markdown table cells.
+var mdCell = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ", "\r", " ")
+
+func formatNumber(p *i18n.Printer, n any) (string, error) {
+	f, err := toFloat(n)
+	if err != nil {
+		return "", err
+	}
+	if f == float64(int(f)) {
+		return p.Int(int(f)), nil
+	}
+	return p.Float(f, 1), nil
+}
+
+func formatPercent(part, total any) (float64, error) {
+	p, err := toFloat(part)
+	if err != nil {
+		return 0, err
+	}
+	t, err := toFloat(total)
+	if err != nil {
+		return 0, err
+	}
+	if t == 0 {
+		return 0, nil
+	}
+	return p / t * 100, nil
+}
+
+func monthOf(v any) (time.Month, error) {
+	var m time.Month
+	switch v := v.(type) {
+	case string:
+		t, err := time.Parse("2006-01", v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid month %q", v)
+		}
+		m = t.Month()
+	default:
+		f, err := toFloat(v)
+		if err != nil {
+			return 0, err
+		}
+		m = time.Month(f)
+	}
+	if m < time.January || m > time.December {
+		return 0, fmt.Errorf("invalid month %v", v)
+	}
+	return m, nil
+}
+
+func topN(n int, list any) (any, error) {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("top: not a list: %T", list)
+	}
+	if n < 0 {
+		n = 0
+	}
+	if n < v.Len() {
+		return v.Slice(0, n).Interface(), nil
+	}
+	return list, nil
+}
+
+func toFloat(v any) (float64, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.String:
+		return strconv.ParseFloat(rv.String(), 64)
+	}
+	return 0, fmt.Errorf("not a number: %v", v)
+}
@@ -0,0 +1,83 @@
+package recap
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/i18n"
+	"github.com/kmdkuk/nfrecap/internal/title"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	run := func(lang, text string) (string, error) {
+		tmpl := template.Must(template.New("").Funcs(templateFuncs(i18n.For(lang))).Parse(text))
+		var b strings.Builder
+		err := tmpl.Execute(&b, nil)
+		return b.String(), err
+	}
+	for _, tt := range []struct {
+		lang, text, want string
+	}{
+		{"ja", `{{hours 90}}`, "1.5"},
+		{"ja", `{{percent 1 3}}`, "33.3"},
+		{"ja", `{{percent 1.5 0}}`, "0.0"},
+		{"ja", `{{monthName 3}} {{monthName "2025-03"}}`, "3月 3月"},
+		{"en", `{{monthName 3}}`, "Mar"},
+		{"ja", `{{num 12345}}`, "12345"},
+		{"en", `{{num 12345}} {{hours 123456}}`, "12,345 2,057.6"},
+		{"ja", `{{T "rank" 1}} {{T "no.such.key"}}`, "1位 no.such.key"},
+		{"en", `{{T "rank" 1}}`, "#1"},
+		{"ja", `{{md "A | B\r\nC"}}`, `A \| B C`},
+	} {
+		out, err := run(tt.lang, tt.text)
+		require.NoError(t, err, tt.text)
+		assert.Equal(t, tt.want, out, tt.text)
+	}
+	for _, text := range []string{`{{percent "x" 1}}`, `{{monthName 13}}`, `{{top 1 "abc"}}`} {
+		_, err := run("ja", text)
+		assert.Error(t, err, text)
+	}
+
+	l, err := topN(2, []int{1, 2, 3})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, l)
+	l, err = topN(5, []string{"a"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, l)
+}
+
+func TestRenderTemplates(t *testing.T) {
+	s := exportTestStats()
+
+	out, err := RenderMarkdownTemplate(s, `{{.Data.Period.Label}} {{hours .Data.Summary.DurationMin}}h {{range top 1 .Data.Titles}}{{.Title}}{{end}} {{monthName (index .Data.Monthly 0).Month}}`)
+	require.NoError(t, err)
+	assert.Equal(t, "2024 3.0h Epic, Part 1 1月", out)
+
+	out, err = RenderMarkdownTemplate(s, `{{svg "calendar"}}`)
+	require.NoError(t, err)
+	assert.Contains(t, out, "<svg ")
+
+	_, err = RenderMarkdownTemplate(s, `{{svg "pie"}}`)
+	assert.ErrorContains(t, err, `unknown chart "pie"`)
+	_, err = RenderMarkdownTemplate(s, `{{.Nope}}`)
+	assert.Error(t, err)
+	_, err = RenderMarkdownTemplate(s, `{{if}}`)
+	assert.ErrorContains(t, err, "failed to parse template")
+
+	s = ComputeStats(build.Built{Items: []build.BuiltItem{
+		{Date: "2024-01-01", Normalized: title.Normalize("<b>Bold</b>")},
+	}}, 2024)
+	out, err = RenderHTMLTemplate(s, HTMLOptions{}, `{{range .Data.Unresolved}}<p>{{.Title}}</p>{{end}}{{svg "weekday"}}`)
+	require.NoError(t, err)
+	assert.Contains(t, out, "<p>&lt;b&gt;Bold&lt;/b&gt;</p>")
+	assert.Contains(t, out, "<svg ")
+
+	// the built-in reports are templates too
+	out, err = RenderMarkdownTemplate(s, BuiltinTemplate(MarkdownTemplateFile))
+	require.NoError(t, err)
+	assert.Equal(t, RenderMarkdown(s), out)
+}
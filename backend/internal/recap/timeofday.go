@@ -0,0 +1,84 @@
+package recap
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// Views starting from lateNightStartHour until before lateNightEndHour
+	// count as late-night viewing.
+	lateNightStartHour = 23
+	lateNightEndHour   = 5
+
+	// sessionGap is the longest pause between two views of one session.
+	sessionGap = 30 * time.Minute
+)
+
+type Session struct {
+	Start       time.Time
+	End         time.Time
+	Views       int
+	DurationMin int
+}
+
+type timedView struct {
+	start time.Time
+	min   int
+}
+
+func isLateNight(hour int) bool {
+	return hour >= lateNightStartHour || hour < lateNightEndHour
+}
+
+// computeTimeOfDay fills the hour-of-day breakdown and sessions from views
+// with a start time. Start times keep the offset chosen at build time, so
+// hours and weekdays are local to that time zone.
+func (s *Stats) computeTimeOfDay(views []timedView) {
+	s.TimedViews = len(views)
+	if len(views) == 0 {
+		return
+	}
+
+	for _, v := range views {
+		h := v.start.Hour()
+		wd := v.start.Weekday()
+		m := s.HourWeekdayHeatmap[wd][h]
+		m.Views++
+		m.DurationMin += v.min
+		s.HourWeekdayHeatmap[wd][h] = m
+		if isLateNight(h) {
+			s.LateNightViews++
+		}
+	}
+	s.LateNightShare = float64(s.LateNightViews) / float64(s.TimedViews) * 100
+
+	sort.Slice(views, func(i, j int) bool { return views[i].start.Before(views[j].start) })
+
+	var sessions []Session
+	cur := Session{Start: views[0].start, End: views[0].start.Add(time.Duration(views[0].min) * time.Minute), Views: 1, DurationMin: views[0].min}
+	for _, v := range views[1:] {
+		end := v.start.Add(time.Duration(v.min) * time.Minute)
+		if !v.start.After(cur.End.Add(sessionGap)) {
+			cur.Views++
+			cur.DurationMin += v.min
+			if end.After(cur.End) {
+				cur.End = end
+			}
+			continue
+		}
+		sessions = append(sessions, cur)
+		cur = Session{Start: v.start, End: end, Views: 1, DurationMin: v.min}
+	}
+	sessions = append(sessions, cur)
+
+	total := 0
+	for _, se := range sessions {
+		total += se.DurationMin
+		if se.DurationMin > s.LongestSession.DurationMin {
+			s.LongestSession = se
+		}
+	}
+	s.SessionCount = len(sessions)
+	s.AvgSessionMin = float64(total) / float64(len(sessions))
+}
@@ -0,0 +1,60 @@
+package recap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeTimeOfDay(t *testing.T) {
+	item := func(start string, durationSec int) build.BuiltItem {
+		return build.BuiltItem{
+			Date:        start[:10],
+			StartTime:   start,
+			DurationSec: durationSec,
+			Normalized:  model.NormalizedTitle{WorkTitle: "Show", Type: "tv"},
+			Metadata:    &model.Metadata{Runtime: 45},
+		}
+	}
+	items := []build.BuiltItem{
+		// Session 1 (Sunday night): three episodes back to back
+		item("2023-01-01T21:00:00+09:00", 45*60),
+		item("2023-01-01T21:50:00+09:00", 45*60),
+		item("2023-01-01T23:00:00+09:00", 30*60), // 25 min pause, same session
+		// Session 2 (Tuesday early morning)
+		item("2023-01-03T01:00:00+09:00", 20*60),
+		// No start time: counted in totals but not in time-of-day stats
+		{Date: "2023-01-04", Normalized: model.NormalizedTitle{WorkTitle: "Movie", Type: "movie"}, Metadata: &model.Metadata{Runtime: 100}},
+	}
+
+	s := ComputeStats(build.Built{Items: items}, 2023)
+
+	assert.Equal(t, 45+45+30+20+100, s.TotalDurationMin) // actual durations override runtime
+	assert.Equal(t, 4, s.TimedViews)
+	assert.Equal(t, Metric{Views: 2, DurationMin: 90}, s.HourWeekdayHeatmap[time.Sunday][21])
+	assert.Equal(t, Metric{Views: 1, DurationMin: 20}, s.HourWeekdayHeatmap[time.Tuesday][1])
+	assert.Equal(t, 2, s.LateNightViews)
+	assert.Equal(t, 50.0, s.LateNightShare)
+
+	assert.Equal(t, 2, s.SessionCount)
+	assert.Equal(t, 70.0, s.AvgSessionMin) // (120 + 20) / 2
+	assert.Equal(t, 120, s.LongestSession.DurationMin)
+	assert.Equal(t, 3, s.LongestSession.Views)
+	assert.Equal(t, "2023-01-01T23:30:00+09:00", s.LongestSession.End.Format(time.RFC3339))
+
+	md := RenderMarkdown(s)
+	assert.Contains(t, md, "| 21時 | 1.5 | 0.0 | 0.0 | 0.0 | 0.0 | 0.0 | 0.0 |")
+	assert.Contains(t, md, "最長セッション：**120 分**（2023-01-01 21:00 〜 23:30、3 本）")
+}
+
+func TestComputeTimeOfDay_NoStartTime(t *testing.T) {
+	s := ComputeStats(build.Built{Items: []build.BuiltItem{
+		{Date: "2023-01-04", Normalized: model.NormalizedTitle{WorkTitle: "Movie"}, Metadata: &model.Metadata{Runtime: 100}},
+	}}, 2023)
+	assert.Equal(t, 0, s.TimedViews)
+	assert.Equal(t, 0, s.SessionCount)
+	assert.NotContains(t, RenderMarkdown(s), "時間帯別")
+}
@@ -0,0 +1,176 @@
+package recap
+
+import (
+	"fmt"
+	"time"
+)
+
+type WeekMetric struct {
+	ISOYear int
+	ISOWeek int
+	Start   time.Time // Monday of the week
+	Metric
+}
+
+// Label is the ISO 8601 week notation, e.g. "2025-W23".
+func (w WeekMetric) Label() string {
+	return fmt.Sprintf("%d-W%02d", w.ISOYear, w.ISOWeek)
+}
+
+type DayTypeStats struct {
+	WeekdayDays   int
+	WeekendDays   int
+	WeekdayMin    int
+	WeekendMin    int
+	WeekdayAvgMin float64 // minutes per calendar day, idle days included
+	WeekendAvgMin float64
+}
+
+// Holiday is a recurring period given as "MM-DD" bounds. To may be before
+// From for periods spanning the new year, e.g. 12-29 to 01-03.
+type Holiday struct {
+	Name string `mapstructure:"name" json:"name"`
+	From string `mapstructure:"from" json:"from"`
+	To   string `mapstructure:"to" json:"to"`
+}
+
+// DefaultHolidays are the major Japanese holiday periods.
+var DefaultHolidays = []Holiday{
+	{Name: "ゴールデンウィーク", From: "04-29", To: "05-05"},
+	{Name: "お盆", From: "08-13", To: "08-16"},
+	{Name: "年末年始", From: "12-29", To: "01-03"},
+}
+
+type HolidayStat struct {
+	Name         string
+	Days         int // days of the holiday within the period
+	ActiveDays   int
+	Views        int
+	DurationMin  int
+	AvgMin       float64 // minutes per day of the holiday
+	BaselineMin  float64 // minutes per day outside all holidays
+	RatioToUsual float64 // AvgMin / BaselineMin, 0 if there is no baseline
+}
+
+// instances returns the concrete date ranges of h that overlap p.
+func (h Holiday) instances(p Period) ([]Period, error) {
+	from, err := time.Parse("01-02", h.From)
+	if err != nil {
+		return nil, fmt.Errorf("holiday %q: invalid from %q: %w", h.Name, h.From, err)
+	}
+	to, err := time.Parse("01-02", h.To)
+	if err != nil {
+		return nil, fmt.Errorf("holiday %q: invalid to %q: %w", h.Name, h.To, err)
+	}
+
+	var out []Period
+	for y := p.From.Year() - 1; y <= p.To.Year(); y++ {
+		f := time.Date(y, from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+		t := time.Date(y, to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+		if t.Before(f) {
+			t = t.AddDate(1, 0, 0)
+		}
+		if t.Before(p.From) || f.After(p.To) {
+			continue
+		}
+		out = append(out, Period{From: f, To: t})
+	}
+	return out, nil
+}
+
+// computeWeekly derives ISO-week, weekday/weekend and holiday figures from
+// s.Daily. Invalid holidays are skipped; validate them with ValidateHolidays.
+func (s *Stats) computeWeekly(holidays []Holiday) {
+	for _, d := range s.Daily {
+		y, w := d.Date.ISOWeek()
+		if n := len(s.WeeklyStats); n == 0 || s.WeeklyStats[n-1].ISOYear != y || s.WeeklyStats[n-1].ISOWeek != w {
+			monday := d.Date.AddDate(0, 0, -((int(d.Date.Weekday()) + 6) % 7))
+			s.WeeklyStats = append(s.WeeklyStats, WeekMetric{ISOYear: y, ISOWeek: w, Start: monday})
+		}
+		wm := &s.WeeklyStats[len(s.WeeklyStats)-1]
+		wm.Views += d.Views
+		wm.DurationMin += d.DurationMin
+
+		if wd := d.Date.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			s.DayTypeStats.WeekendDays++
+			s.DayTypeStats.WeekendMin += d.DurationMin
+		} else {
+			s.DayTypeStats.WeekdayDays++
+			s.DayTypeStats.WeekdayMin += d.DurationMin
+		}
+	}
+	for _, w := range s.WeeklyStats {
+		if w.DurationMin > s.BusiestWeek.DurationMin {
+			s.BusiestWeek = w
+		}
+	}
+	dt := &s.DayTypeStats
+	if dt.WeekdayDays > 0 {
+		dt.WeekdayAvgMin = float64(dt.WeekdayMin) / float64(dt.WeekdayDays)
+	}
+	if dt.WeekendDays > 0 {
+		dt.WeekendAvgMin = float64(dt.WeekendMin) / float64(dt.WeekendDays)
+	}
+
+	s.computeHolidays(holidays)
+}
+
+func (s *Stats) computeHolidays(holidays []Holiday) {
+	inAny := make(map[time.Time]bool)
+	stats := make([]HolidayStat, 0, len(holidays))
+	for _, h := range holidays {
+		insts, err := h.instances(s.Period)
+		if err != nil || len(insts) == 0 {
+			continue
+		}
+		hs := HolidayStat{Name: h.Name}
+		for _, d := range s.Daily {
+			for _, in := range insts {
+				if !in.Contains(d.Date) {
+					continue
+				}
+				inAny[d.Date] = true
+				hs.Days++
+				hs.Views += d.Views
+				hs.DurationMin += d.DurationMin
+				if d.Views > 0 {
+					hs.ActiveDays++
+				}
+				break
+			}
+		}
+		if hs.Days > 0 {
+			hs.AvgMin = float64(hs.DurationMin) / float64(hs.Days)
+		}
+		stats = append(stats, hs)
+	}
+
+	usualDays, usualMin := 0, 0
+	for _, d := range s.Daily {
+		if !inAny[d.Date] {
+			usualDays++
+			usualMin += d.DurationMin
+		}
+	}
+	baseline := 0.0
+	if usualDays > 0 {
+		baseline = float64(usualMin) / float64(usualDays)
+	}
+	for i := range stats {
+		stats[i].BaselineMin = baseline
+		if baseline > 0 {
+			stats[i].RatioToUsual = stats[i].AvgMin / baseline
+		}
+	}
+	s.HolidayStats = stats
+}
+
+// ValidateHolidays reports the first holiday with malformed dates.
+func ValidateHolidays(holidays []Holiday) error {
+	for _, h := range holidays {
+		if _, err := h.instances(YearPeriod(2000)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package recap
+
+import (
+	"testing"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeWeekly(t *testing.T) {
+	item := func(date string, runtime int) build.BuiltItem {
+		return build.BuiltItem{Date: date, Normalized: model.NormalizedTitle{WorkTitle: "A"}, Metadata: &model.Metadata{Runtime: runtime}}
+	}
+	items := []build.BuiltItem{
+		item("2021-01-01", 30),  // Friday, ISO week 2020-W53
+		item("2021-01-02", 60),  // Saturday
+		item("2021-01-04", 100), // Monday, 2021-W01
+		item("2021-01-05", 100),
+		item("2021-05-03", 70), // Golden Week
+	}
+
+	s := ComputeStatsWithOptions(build.Built{Items: items}, Options{Period: YearPeriod(2021)})
+
+	require.NotEmpty(t, s.WeeklyStats)
+	first := s.WeeklyStats[0]
+	assert.Equal(t, "2020-W53", first.Label())
+	assert.Equal(t, "2020-12-28", first.Start.Format(dateLayout))
+	assert.Equal(t, Metric{Views: 2, DurationMin: 90}, first.Metric) // only days inside the period
+	assert.Equal(t, "2021-W01", s.BusiestWeek.Label())
+	assert.Equal(t, 200, s.BusiestWeek.DurationMin)
+	assert.Equal(t, "2021-W52", s.WeeklyStats[len(s.WeeklyStats)-1].Label())
+
+	dt := s.DayTypeStats
+	assert.Equal(t, 261, dt.WeekdayDays)
+	assert.Equal(t, 104, dt.WeekendDays)
+	assert.Equal(t, 30+100+100+70, dt.WeekdayMin)
+	assert.Equal(t, 60, dt.WeekendMin)
+	assert.InDelta(t, 60.0/104, dt.WeekendAvgMin, 1e-9)
+
+	if assert.Len(t, s.HolidayStats, 3) {
+		gw := s.HolidayStats[0]
+		assert.Equal(t, "ゴールデンウィーク", gw.Name)
+		assert.Equal(t, 7, gw.Days)
+		assert.Equal(t, 1, gw.ActiveDays)
+		assert.Equal(t, 70, gw.DurationMin)
+		assert.InDelta(t, 10.0, gw.AvgMin, 1e-9)
+
+		ny := s.HolidayStats[2]
+		assert.Equal(t, "年末年始", ny.Name)
+		assert.Equal(t, 3+3, ny.Days) // Jan 1-3 and Dec 29-31
+		assert.Equal(t, 2, ny.ActiveDays)
+		assert.Equal(t, 90, ny.DurationMin)
+
+		// 365 days - 7 - 4 - 6 holiday days, 200 minutes outside holidays
+		assert.InDelta(t, 200.0/348, gw.BaselineMin, 1e-9)
+		assert.InDelta(t, 10.0/(200.0/348), gw.RatioToUsual, 1e-9)
+	}
+}
+
+func TestHolidays(t *testing.T) {
+	custom := []Holiday{{Name: "Winter", From: "12-20", To: "01-10"}}
+	s := ComputeStatsWithOptions(build.Built{}, Options{Period: YearPeriod(2024), Holidays: custom})
+	if assert.Len(t, s.HolidayStats, 1) {
+		assert.Equal(t, 10+12, s.HolidayStats[0].Days)
+	}
+
+	assert.NoError(t, ValidateHolidays(DefaultHolidays))
+	assert.ErrorContains(t, ValidateHolidays([]Holiday{{Name: "Bad", From: "02-30", To: "03-01"}}), `holiday "Bad"`)
+}
@@ -1,11 +1,25 @@
 package title
 
 import (
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/kmdkuk/nfrecap/internal/model"
 )
 
+var (
+	// "シーズン1", "Season 1", "第1シーズン", "シーズン 2"
+	seasonNumberRe = regexp.MustCompile(`(?i)(?:シーズン|season)\s*(\d+)|第\s*(\d+)\s*シーズン`)
+	// "第3話", "エピソード3", "Episode 3", "Chapter 3", "Ep. 3"
+	episodeNumberRe = regexp.MustCompile(`(?i)^(?:第\s*(\d+)\s*話|(?:エピソード|episode|chapter|ep\.?)\s*(\d+))`)
+
+	fullWidthDigits = strings.NewReplacer(
+		"０", "0", "１", "1", "２", "2", "３", "3", "４", "4",
+		"５", "5", "６", "6", "７", "7", "８", "8", "９", "9",
+	)
+)
+
 func Normalize(raw string) model.NormalizedTitle {
 	s := strings.TrimSpace(raw)
 	n := model.NormalizedTitle{
@@ -16,17 +30,49 @@ func Normalize(raw string) model.NormalizedTitle {
 
 	// Common Netflix JP format: "作品名: シーズン1: 話タイトル"
 	parts := strings.Split(s, ":")
-	if len(parts) >= 1 {
-		n.WorkTitle = strings.TrimSpace(parts[0])
+	if strings.TrimSpace(parts[0]) == "" {
+		return n // nothing before the first colon: keep the whole title as a work
 	}
+	n.WorkTitle = strings.TrimSpace(parts[0])
 
 	if len(parts) >= 2 {
 		n.Type = "tv"
 		n.Season = strings.TrimSpace(parts[1])
+		n.SeasonNumber = SeasonNumber(n.Season)
 	}
 	if len(parts) >= 3 {
 		n.EpisodeTitle = strings.TrimSpace(parts[2])
+		n.EpisodeNumber = EpisodeNumber(n.EpisodeTitle)
 	}
 
 	return n
 }
+
+// SeasonNumber parses the number of a season title such as "シーズン2",
+// or returns 0.
+func SeasonNumber(season string) int {
+	return firstNumber(seasonNumberRe, season)
+}
+
+// EpisodeNumber parses the number of an episode title such as "第3話",
+// or returns 0.
+func EpisodeNumber(episode string) int {
+	return firstNumber(episodeNumberRe, episode)
+}
+
+// firstNumber returns the first captured number of re in s, or 0 if none.
+func firstNumber(re *regexp.Regexp, s string) int {
+	m := re.FindStringSubmatch(fullWidthDigits.Replace(s))
+	if m == nil {
+		return 0
+	}
+	for _, g := range m[1:] {
+		if g == "" {
+			continue
+		}
+		if v, err := strconv.Atoi(g); err == nil {
+			return v
+		}
+	}
+	return 0
+}
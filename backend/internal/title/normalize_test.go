@@ -13,6 +13,15 @@ func TestNormalize(t *testing.T) {
 		input    string
 		expected model.NormalizedTitle
 	}{
+		{
+			name:  "Leading colon",
+			input: ": Ep",
+			expected: model.NormalizedTitle{
+				RawTitle:  ": Ep",
+				WorkTitle: ": Ep",
+				Type:      "movie",
+			},
+		},
 		{
 			name:  "Movie basic",
 			input: "Inception",
@@ -26,10 +35,11 @@ func TestNormalize(t *testing.T) {
 			name:  "TV Show with Season",
 			input: "Stranger Things: Season 1",
 			expected: model.NormalizedTitle{
-				RawTitle:  "Stranger Things: Season 1",
-				WorkTitle: "Stranger Things",
-				Type:      "tv",
-				Season:    "Season 1",
+				RawTitle:     "Stranger Things: Season 1",
+				WorkTitle:    "Stranger Things",
+				Type:         "tv",
+				Season:       "Season 1",
+				SeasonNumber: 1,
 			},
 		},
 		{
@@ -41,16 +51,56 @@ func TestNormalize(t *testing.T) {
 				Type:         "tv",
 				Season:       "Season 3",
 				EpisodeTitle: "Nosedive",
+				SeasonNumber: 3,
 			},
 		},
 		{
 			name:  "Whitespace handling",
 			input: "  Breaking Bad : Season 5  ",
 			expected: model.NormalizedTitle{
-				RawTitle:  "Breaking Bad : Season 5",
-				WorkTitle: "Breaking Bad",
-				Type:      "tv",
-				Season:    "Season 5",
+				RawTitle:     "Breaking Bad : Season 5",
+				WorkTitle:    "Breaking Bad",
+				Type:         "tv",
+				Season:       "Season 5",
+				SeasonNumber: 5,
+			},
+		},
+		{
+			name:  "Japanese season and episode numbers",
+			input: "ストレンジャー・シングス 未知の世界: シーズン1: 第3話 ホリー・ジョリー",
+			expected: model.NormalizedTitle{
+				RawTitle:      "ストレンジャー・シングス 未知の世界: シーズン1: 第3話 ホリー・ジョリー",
+				WorkTitle:     "ストレンジャー・シングス 未知の世界",
+				Type:          "tv",
+				Season:        "シーズン1",
+				EpisodeTitle:  "第3話 ホリー・ジョリー",
+				SeasonNumber:  1,
+				EpisodeNumber: 3,
+			},
+		},
+		{
+			name:  "Full-width digits and English episode",
+			input: "Dark: 第２シーズン: Episode 7",
+			expected: model.NormalizedTitle{
+				RawTitle:      "Dark: 第２シーズン: Episode 7",
+				WorkTitle:     "Dark",
+				Type:          "tv",
+				Season:        "第２シーズン",
+				EpisodeTitle:  "Episode 7",
+				SeasonNumber:  2,
+				EpisodeNumber: 7,
+			},
+		},
+		{
+			name:  "Limited series without season number",
+			input: "クイーンズ・ギャンビット: リミテッドシリーズ: エピソード1",
+			expected: model.NormalizedTitle{
+				RawTitle:      "クイーンズ・ギャンビット: リミテッドシリーズ: エピソード1",
+				WorkTitle:     "クイーンズ・ギャンビット",
+				Type:          "tv",
+				Season:        "リミテッドシリーズ",
+				EpisodeTitle:  "エピソード1",
+				EpisodeNumber: 1,
 			},
 		},
 	}
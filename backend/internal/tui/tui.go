@@ -0,0 +1,515 @@
+// Package tui is an interactive terminal dashboard for exploring a recap:
+// tabs for the overview, months, genres, titles and series, drill-down from
+// a month to its days and from a genre to its titles, and year switching.
+// Everything shown comes from recap.Stats, as in the reports.
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/kmdkuk/nfrecap/internal/i18n"
+	"github.com/kmdkuk/nfrecap/internal/recap"
+)
+
+// Options configures the dashboard.
+type Options struct {
+	Years []int // years to switch between, in order
+	Year  int   // year shown first; the last of Years if zero
+
+	// Stats computes the recap of a year. Results are cached.
+	Stats func(year int) recap.Stats
+}
+
+// Run shows the dashboard until the user quits.
+func Run(opts Options) error {
+	m, err := New(opts)
+	if err != nil {
+		return err
+	}
+	_, err = tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+type tab int
+
+const (
+	tabOverview tab = iota
+	tabMonths
+	tabGenres
+	tabTitles
+	tabSeries
+	tabCount
+)
+
+var tabKeys = [tabCount]string{"tui.tab.overview", "tui.tab.months", "tui.tab.genres", "tui.tab.titles", "tui.tab.series"}
+
+// Model is the bubbletea model of the dashboard.
+type Model struct {
+	opts   Options
+	year   int // index into opts.Years
+	cache  map[int]recap.Stats
+	stats  recap.Stats
+	p      *i18n.Printer
+	tab    tab
+	cursor [tabCount]int
+	drill  *table // detail list opened with enter, nil if none
+	width  int
+	height int
+}
+
+// New returns the dashboard model for opts, showing opts.Year.
+func New(opts Options) (Model, error) {
+	if len(opts.Years) == 0 {
+		return Model{}, errors.New("no views to show")
+	}
+	m := Model{opts: opts, cache: make(map[int]recap.Stats), year: len(opts.Years) - 1}
+	if opts.Year != 0 {
+		m.year = slices.Index(opts.Years, opts.Year)
+		if m.year < 0 {
+			return Model{}, fmt.Errorf("no views in %d", opts.Year)
+		}
+	}
+	m.load()
+	return m, nil
+}
+
+// load computes (or takes from the cache) the stats of the current year.
+func (m *Model) load() {
+	y := m.opts.Years[m.year]
+	s, ok := m.cache[y]
+	if !ok {
+		s = m.opts.Stats(y)
+		m.cache[y] = s
+	}
+	m.stats = s
+	m.p = i18n.For(s.Config.Lang)
+	m.drill = nil
+	for t := range m.cursor {
+		if l := m.list(tab(t)); l != nil {
+			m.cursor[t] = clamp(m.cursor[t], len(l.rows))
+		}
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		switch key := msg.String(); key {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "tab", "right", "l":
+			m.switchTab((m.tab + 1) % tabCount)
+		case "shift+tab", "left", "h":
+			m.switchTab((m.tab + tabCount - 1) % tabCount)
+		case "1", "2", "3", "4", "5":
+			m.switchTab(tab(key[0] - '1'))
+		case "[":
+			if m.year > 0 {
+				m.year--
+				m.load()
+			}
+		case "]":
+			if m.year < len(m.opts.Years)-1 {
+				m.year++
+				m.load()
+			}
+		case "up", "k":
+			m.move(-1)
+		case "down", "j":
+			m.move(1)
+		case "pgup":
+			m.move(-m.pageSize())
+		case "pgdown":
+			m.move(m.pageSize())
+		case "home", "g":
+			m.move(-1 << 30)
+		case "end", "G":
+			m.move(1 << 30)
+		case "enter":
+			if m.drill == nil {
+				m.drill = m.detail()
+			}
+		case "esc", "backspace":
+			m.drill = nil
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) switchTab(t tab) {
+	m.tab = t
+	m.drill = nil
+}
+
+// move moves the cursor of the shown list by delta rows.
+func (m *Model) move(delta int) {
+	if m.drill != nil {
+		m.drill.cursor = clamp(m.drill.cursor+delta, len(m.drill.rows))
+		return
+	}
+	if l := m.list(m.tab); l != nil {
+		m.cursor[m.tab] = clamp(m.cursor[m.tab]+delta, len(l.rows))
+	}
+}
+
+func clamp(i, n int) int {
+	return max(0, min(i, n-1))
+}
+
+// chrome is the number of lines around a list: the header, tabs, list title,
+// column headers and help.
+const chrome = 8
+
+func (m Model) pageSize() int {
+	return max(1, m.height-chrome)
+}
+
+// table is a list of rows with a cursor.
+type table struct {
+	title  string
+	header []string
+	right  []bool // right-aligned columns
+	rows   [][]string
+	bars   []float64 // a bar after each row, scaled to the largest; optional
+	cursor int
+}
+
+// list returns the list of tab t at its cursor, or nil for the overview.
+func (m Model) list(t tab) *table {
+	var l *table
+	switch t {
+	case tabMonths:
+		l = m.monthsTable()
+	case tabGenres:
+		l = m.genresTable()
+	case tabTitles:
+		l = m.titlesTable()
+	case tabSeries:
+		l = m.seriesTable()
+	default:
+		return nil
+	}
+	l.cursor = m.cursor[t]
+	return l
+}
+
+func (m Model) monthsTable() *table {
+	p := m.p
+	l := &table{
+		title:  p.T("monthly.title"),
+		header: []string{p.T("col.month"), p.T("col.views"), p.T("col.hours")},
+		right:  []bool{false, true, true},
+	}
+	for _, mm := range m.stats.MonthlySeries {
+		l.rows = append(l.rows, []string{p.Month(mm.Month), p.Int(mm.Views), p.Hours(mm.DurationMin)})
+		l.bars = append(l.bars, float64(mm.DurationMin))
+	}
+	return l
+}
+
+func (m Model) genresTable() *table {
+	p := m.p
+	l := &table{
+		title:  p.T("html.genres"),
+		header: []string{p.T("col.genre"), p.T("col.hours"), p.T("col.share"), p.T("col.views")},
+		right:  []bool{false, true, true, true},
+	}
+	for _, g := range m.stats.GenreStats {
+		l.rows = append(l.rows, []string{g.Name, p.Hours(g.DurationMin), p.Float(g.Share, 1) + "%", p.Int(g.Views)})
+		l.bars = append(l.bars, float64(g.DurationMin))
+	}
+	return l
+}
+
+// rankedTitles returns the title ranking by the configured sort key.
+func (m Model) rankedTitles() []recap.TitleStat {
+	if m.stats.Config.SortBy == recap.SortByViews {
+		return m.stats.TopTitlesByViews
+	}
+	return m.stats.TopTitlesByDuration
+}
+
+func (m Model) titlesTable() *table {
+	return m.newTitlesTable(m.p.T("titles.title", m.sortLabel(), len(m.rankedTitles())), m.rankedTitles())
+}
+
+func (m Model) newTitlesTable(title string, ts []recap.TitleStat) *table {
+	p := m.p
+	l := &table{
+		title:  title,
+		header: []string{p.T("col.rank"), p.T("col.title"), p.T("col.type"), p.T("col.hours"), p.T("col.views")},
+		right:  []bool{true, false, false, true, true},
+	}
+	for i, t := range ts {
+		l.rows = append(l.rows, []string{p.T("rank", i+1), t.Title, t.Type, p.Hours(t.DurationMin), p.Int(t.Views)})
+	}
+	return l
+}
+
+func (m Model) seriesTable() *table {
+	p := m.p
+	series := m.stats.TopSeriesByDuration
+	if m.stats.Config.SortBy == recap.SortByViews {
+		series = m.stats.TopSeriesByViews
+	}
+	l := &table{
+		title:  p.T("series.title", m.sortLabel()),
+		header: []string{p.T("col.rank"), p.T("col.series"), p.T("col.views"), p.T("col.hours"), p.T("col.series_span")},
+		right:  []bool{true, false, true, true, false},
+	}
+	for i, s := range series {
+		span := p.T("span", p.Date(s.SpanStart), p.Date(s.SpanEnd))
+		l.rows = append(l.rows, []string{p.T("rank", i+1), s.SeriesName, p.Int(s.Views), p.Hours(s.DurationMin), span})
+	}
+	return l
+}
+
+func (m Model) sortLabel() string {
+	if m.stats.Config.SortBy == recap.SortByViews {
+		return m.p.T("sort.views")
+	}
+	return m.p.T("sort.duration")
+}
+
+// detail returns the drill-down of the selected row: the days of a month or
+// the titles of a genre. It returns nil if the tab has none.
+func (m Model) detail() *table {
+	p := m.p
+	i := m.cursor[m.tab]
+	switch m.tab {
+	case tabMonths:
+		if i >= len(m.stats.MonthlySeries) {
+			return nil
+		}
+		mm := m.stats.MonthlySeries[i]
+		l := &table{
+			title:  p.T("tui.month_days", p.YearMonth(mm.Year, mm.Month)),
+			header: []string{p.T("tui.col.day"), p.T("col.weekday"), p.T("col.views"), p.T("col.hours")},
+			right:  []bool{false, false, true, true},
+		}
+		for _, d := range m.stats.Daily {
+			if d.Date.Year() != mm.Year || d.Date.Month() != mm.Month {
+				continue
+			}
+			l.rows = append(l.rows, []string{p.Date(d.Date), p.Weekday(d.Date.Weekday()), p.Int(d.Views), p.Hours(d.DurationMin)})
+			l.bars = append(l.bars, float64(d.DurationMin))
+		}
+		return l
+	case tabGenres:
+		if i >= len(m.stats.GenreStats) {
+			return nil
+		}
+		g := m.stats.GenreStats[i]
+		var ts []recap.TitleStat
+		for _, t := range m.stats.RankedTitles() { // not only the top titles
+			if slices.Contains(t.Genres, g.Key) {
+				ts = append(ts, t)
+			}
+		}
+		return m.newTitlesTable(p.T("tui.genre_titles", g.Name), ts)
+	}
+	return nil
+}
+
+var (
+	accent       = lipgloss.Color("#e50914")
+	headerStyle  = lipgloss.NewStyle().Bold(true)
+	activeTab    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#ffffff")).Background(accent).Padding(0, 1)
+	inactiveTab  = lipgloss.NewStyle().Faint(true).Padding(0, 1)
+	titleStyle   = lipgloss.NewStyle().Bold(true).Underline(true)
+	mutedStyle   = lipgloss.NewStyle().Faint(true)
+	selectedRow  = lipgloss.NewStyle().Reverse(true)
+	barStyle     = lipgloss.NewStyle().Foreground(accent)
+	currentYear  = lipgloss.NewStyle().Bold(true).Foreground(accent)
+	barWidth     = 20
+	maxCellWidth = 40
+)
+
+func (m Model) View() string {
+	var b strings.Builder
+	b.WriteString(headerStyle.Render("Netflix Recap") + "  " + m.yearsView() + "\n\n")
+
+	tabs := make([]string, tabCount)
+	for t := range tabCount {
+		style := inactiveTab
+		if t == m.tab {
+			style = activeTab
+		}
+		tabs[t] = style.Render(fmt.Sprintf("%d %s", t+1, m.p.T(tabKeys[t])))
+	}
+	b.WriteString(strings.Join(tabs, " ") + "\n\n")
+
+	switch l := m.list(m.tab); {
+	case m.drill != nil:
+		b.WriteString(m.tableView(m.drill))
+	case l != nil:
+		b.WriteString(m.tableView(l))
+	default:
+		b.WriteString(m.overviewView())
+	}
+
+	help := m.p.T("tui.help")
+	if m.drill != nil {
+		help = m.p.T("tui.help_drill")
+	}
+	b.WriteString("\n" + mutedStyle.Render(help))
+	return b.String()
+}
+
+func (m Model) yearsView() string {
+	years := make([]string, len(m.opts.Years))
+	for i, y := range m.opts.Years {
+		if i == m.year {
+			years[i] = currentYear.Render(fmt.Sprint(y))
+		} else {
+			years[i] = mutedStyle.Render(fmt.Sprint(y))
+		}
+	}
+	return strings.Join(years, " ")
+}
+
+func (m Model) overviewView() string {
+	s, p := m.stats, m.p
+	if s.TotalViews == 0 {
+		return p.T("tui.empty") + "\n"
+	}
+	topGenre, topSeries, streak := "-", "-", "-"
+	if len(s.GenreStats) > 0 {
+		topGenre = s.GenreStats[0].Name
+	}
+	if len(s.TopSeriesByDuration) > 0 {
+		topSeries = s.TopSeriesByDuration[0].SeriesName
+		if s.Config.SortBy == recap.SortByViews {
+			topSeries = s.TopSeriesByViews[0].SeriesName
+		}
+	}
+	if len(s.TopStreaks) > 0 {
+		streak = p.T("days", s.TopStreaks[0].Days)
+	}
+	covered := s.TotalViews - s.UnresolvedCount
+	facts := [][]string{
+		{p.T("card.total_hours"), p.Hours(s.TotalDurationMin) + " " + p.T("card.hours_unit")},
+		{p.T("col.views"), p.Int(s.TotalViews)},
+		{p.T("col.active_days"), fmt.Sprintf("%s (%s%%)", p.Int(s.ActiveDays), p.Float(percent(s.ActiveDays, s.PeriodDays), 1))},
+		{p.T("card.longest_streak"), streak},
+		{p.T("card.top_genre"), topGenre},
+		{p.T("card.top_series"), topSeries},
+		{p.T("html.card_coverage"), fmt.Sprintf("%s / %s (%s%%)", p.Int(covered), p.Int(s.TotalViews), p.Float(percent(covered, s.TotalViews), 1))},
+	}
+	l := &table{right: []bool{false, false}, rows: facts, cursor: -1}
+
+	var b strings.Builder
+	b.WriteString(m.tableView(l))
+	b.WriteString("\n" + mutedStyle.Render(p.T("card.monthly")) + "\n")
+	var labels, sparks []string
+	maxV := 0
+	for _, mm := range s.MonthlySeries {
+		maxV = max(maxV, mm.DurationMin)
+	}
+	for _, mm := range s.MonthlySeries {
+		label := p.Month(mm.Month)
+		w := max(lipgloss.Width(label), 2)
+		labels = append(labels, pad(label, w, false))
+		sparks = append(sparks, pad(spark(mm.DurationMin, maxV), w, false))
+	}
+	b.WriteString(barStyle.Render(strings.Join(sparks, " ")) + "\n")
+	b.WriteString(mutedStyle.Render(strings.Join(labels, " ")) + "\n")
+	return b.String()
+}
+
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+func spark(v, maxV int) string {
+	if maxV == 0 || v == 0 {
+		return " "
+	}
+	return string(sparkLevels[(v*(len(sparkLevels)-1)+maxV-1)/maxV])
+}
+
+// tableView renders l with aligned columns, showing the rows around the
+// cursor that fit into the terminal.
+func (m Model) tableView(l *table) string {
+	var b strings.Builder
+	if l.title != "" {
+		b.WriteString(titleStyle.Render(l.title) + "\n")
+	}
+	if len(l.rows) == 0 {
+		b.WriteString(m.p.T("tui.empty") + "\n")
+		return b.String()
+	}
+
+	widths := make([]int, len(l.right))
+	for _, row := range append([][]string{l.header}, l.rows...) {
+		for i, cell := range row {
+			widths[i] = min(max(widths[i], lipgloss.Width(cell)), maxCellWidth)
+		}
+	}
+	line := func(row []string) string {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = pad(cell, widths[i], l.right[i])
+		}
+		return strings.Join(cells, "  ")
+	}
+	if l.header != nil {
+		b.WriteString(mutedStyle.Render(line(l.header)) + "\n")
+	}
+
+	maxV := slices.Max(append([]float64{0}, l.bars...))
+	first, last := 0, len(l.rows)
+	if m.height > 0 && l.cursor >= 0 {
+		page := m.pageSize()
+		first = max(0, min(l.cursor-page/2, len(l.rows)-page))
+		last = min(len(l.rows), first+page)
+	}
+	for i := first; i < last; i++ {
+		text := line(l.rows[i])
+		if i == l.cursor {
+			text = selectedRow.Render(text)
+		}
+		if l.bars != nil && maxV > 0 {
+			text += " " + barStyle.Render(strings.Repeat("█", int(l.bars[i]/maxV*float64(barWidth)+0.5)))
+		}
+		b.WriteString(text + "\n")
+	}
+	return b.String()
+}
+
+// pad fits s into width display columns, cutting it with "…" if needed.
+func pad(s string, width int, right bool) string {
+	if lipgloss.Width(s) > width {
+		s = truncate(s, width)
+	}
+	gap := strings.Repeat(" ", width-lipgloss.Width(s))
+	if right {
+		return gap + s
+	}
+	return s + gap
+}
+
+func truncate(s string, width int) string {
+	r := []rune(s)
+	for len(r) > 0 && lipgloss.Width(string(r))+1 > width {
+		r = r[:len(r)-1]
+	}
+	return string(r) + "…"
+}
@@ -0,0 +1,185 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kmdkuk/nfrecap/internal/build"
+	"github.com/kmdkuk/nfrecap/internal/model"
+	"github.com/kmdkuk/nfrecap/internal/recap"
+	"github.com/kmdkuk/nfrecap/internal/title"
+)
+
+func testBuilt() build.Built {
+	md := func(runtime int, genres ...string) *model.Metadata {
+		return &model.Metadata{Runtime: runtime, Genres: genres}
+	}
+	return build.Built{Items: []build.BuiltItem{
+		{Date: "2023-05-01", Normalized: title.Normalize("Old Film"), Metadata: md(90, "Drama")},
+		{Date: "2024-01-06", Normalized: title.Normalize("Epic"), Metadata: md(120, "Drama")},
+		{Date: "2024-01-07", Normalized: title.Normalize("Show: シーズン1: 第1話"), Metadata: md(30, "Comedy")},
+		{Date: "2024-03-02", Normalized: title.Normalize("Show: シーズン1: 第2話"), Metadata: md(30, "Comedy")},
+	}}
+}
+
+func newTestModel(t *testing.T, lang string) (Model, *[]int) {
+	t.Helper()
+	built := testBuilt()
+	var computed []int
+	m, err := New(Options{
+		Years: recap.Years(built),
+		Stats: func(year int) recap.Stats {
+			computed = append(computed, year)
+			return recap.ComputeStatsWithOptions(built, recap.Options{
+				Period: recap.YearPeriod(year),
+				Config: recap.Config{Lang: lang},
+			})
+		},
+	})
+	require.NoError(t, err)
+	return m, &computed
+}
+
+func press(m Model, keys ...string) Model {
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		next, _ := m.Update(msg)
+		m = next.(Model)
+	}
+	return m
+}
+
+func TestNew(t *testing.T) {
+	m, computed := newTestModel(t, "")
+	assert.Equal(t, []int{2024}, *computed) // the latest year
+	assert.Contains(t, m.View(), "推定視聴時間")
+
+	_, err := New(Options{Years: []int{2024}, Year: 2020})
+	assert.ErrorContains(t, err, "no views in 2020")
+	_, err = New(Options{})
+	assert.Error(t, err)
+}
+
+func TestTabs(t *testing.T) {
+	m, _ := newTestModel(t, "en")
+	assert.Contains(t, m.View(), "Estimated watch time")
+
+	m = press(m, "tab")
+	assert.Equal(t, tabMonths, m.tab)
+	assert.Contains(t, m.View(), "Watch Time and Views by Month")
+	m = press(m, "5")
+	assert.Equal(t, tabSeries, m.tab)
+	assert.Contains(t, m.View(), "Show")
+	m = press(m, "tab")
+	assert.Equal(t, tabOverview, m.tab)
+	m = press(m, "h")
+	assert.Equal(t, tabSeries, m.tab)
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	assert.NotNil(t, cmd)
+	assert.IsType(t, Model{}, next)
+}
+
+func TestDrillMonth(t *testing.T) {
+	m, _ := newTestModel(t, "en")
+	m = press(m, "2", "enter")
+	require.NotNil(t, m.drill)
+	assert.Equal(t, "Days of Jan 2024", m.drill.title)
+	require.Len(t, m.drill.rows, 31)
+	assert.Equal(t, []string{"Jan 6, 2024", "Sat", "1", "2.0"}, m.drill.rows[5])
+
+	m = press(m, "esc")
+	assert.Nil(t, m.drill)
+	m = press(m, "j", "j", "enter")
+	assert.Equal(t, "Days of Mar 2024", m.drill.title)
+}
+
+func TestDrillGenre(t *testing.T) {
+	m, _ := newTestModel(t, "en")
+	m = press(m, "3")
+	view := m.View()
+	assert.Contains(t, view, "Drama")
+	assert.Contains(t, view, "Comedy")
+
+	m = press(m, "down", "enter")
+	require.NotNil(t, m.drill)
+	assert.Equal(t, "Titles in Comedy", m.drill.title)
+	require.Len(t, m.drill.rows, 1)
+	assert.Equal(t, "Show", m.drill.rows[0][1])
+
+	// titles and series have no drill-down
+	m = press(m, "4", "enter")
+	assert.Nil(t, m.drill)
+}
+
+func TestDrillGenreBeyondTopTitles(t *testing.T) {
+	built := testBuilt()
+	m, err := New(Options{
+		Years: recap.Years(built),
+		Stats: func(year int) recap.Stats {
+			return recap.ComputeStatsWithOptions(built, recap.Options{
+				Period: recap.YearPeriod(year),
+				Config: recap.Config{Lang: "en", Limits: recap.Limits{Titles: 1}},
+			})
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, m.stats.TopTitlesByDuration, 1) // Epic
+
+	m = press(m, "3", "down", "enter")
+	require.NotNil(t, m.drill)
+	require.Len(t, m.drill.rows, 1)
+	assert.Equal(t, "Show", m.drill.rows[0][1])
+}
+
+func TestYearSwitch(t *testing.T) {
+	m, computed := newTestModel(t, "")
+	m = press(m, "3", "down", "enter")
+	require.NotNil(t, m.drill)
+
+	m = press(m, "[")
+	assert.Equal(t, 2023, m.stats.Year)
+	assert.Nil(t, m.drill)
+	assert.Equal(t, 0, m.cursor[tabGenres]) // 2023 has a single genre
+	m = press(m, "[")
+	assert.Equal(t, 2023, m.stats.Year)
+
+	m = press(m, "]", "]")
+	assert.Equal(t, 2024, m.stats.Year)
+	assert.Equal(t, []int{2024, 2023}, *computed) // cached
+}
+
+func TestScrolling(t *testing.T) {
+	m, _ := newTestModel(t, "en")
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: chrome + 5})
+	m = next.(Model)
+	m = press(m, "2", "enter", "G")
+	assert.Equal(t, 30, m.drill.cursor)
+
+	view := m.View()
+	assert.Contains(t, view, "Jan 31, 2024")
+	assert.NotContains(t, view, "Jan 1, 2024")
+	assert.Equal(t, 5, strings.Count(view, ", 2024"))
+}
+
+func TestPad(t *testing.T) {
+	assert.Equal(t, "ab  ", pad("ab", 4, false))
+	assert.Equal(t, "  ab", pad("ab", 4, true))
+	assert.Equal(t, "日本…", pad("日本語です", 5, false))
+}
@@ -1,14 +1,16 @@
 import React, { useState } from 'react';
 import './App.css';
-import type { ApiResponse, Stats } from './types';
+import type { ApiResponse, CompactCalendar, Stats } from './types';
 import { UploadSection } from './components/UploadSection';
 import { StatsSummary } from './components/StatsSummary';
 import { GenreTable } from './components/GenreTable';
 import { TitleTable } from './components/TitleTable';
+import { CalendarHeatmap } from './components/CalendarHeatmap';
 
 function App() {
   const [file, setFile] = useState<File | null>(null);
   const [data, setData] = useState<Stats | null>(null);
+  const [calendar, setCalendar] = useState<CompactCalendar | null>(null);
   const [loading, setLoading] = useState(false);
   const [error, setError] = useState<string | null>(null);
 
@@ -25,6 +27,7 @@ function App() {
     setLoading(true);
     setError(null);
     setData(null);
+    setCalendar(null);
 
     const formData = new FormData();
     formData.append('file', file);
@@ -40,6 +43,7 @@ function App() {
       }
       const json: ApiResponse = await res.json();
       setData(json.recap);
+      setCalendar(json.calendar);
     } catch (err: unknown) {
       if (err instanceof Error) {
         setError(err.message);
@@ -72,6 +76,8 @@ function App() {
 
           <StatsSummary data={data} />
 
+          {calendar && <CalendarHeatmap calendar={calendar} />}
+
           <GenreTable stats={data.GenreStats} />
 
           <TitleTable
@@ -0,0 +1,60 @@
+import React from 'react';
+import type { CompactCalendar } from '../types';
+import { formatMinLong } from '../utils/format';
+
+interface CalendarHeatmapProps {
+    calendar: CompactCalendar;
+}
+
+const CELL = 11;
+const GAP = 2;
+const STEP = CELL + GAP;
+const COLORS = ['#2d2d2d', '#5c1a17', '#8c0a0e', '#c4111a', '#e50914'];
+
+// Quartiles of active-day minutes, matching the backend SVG renderer.
+const thresholds = (minutes: number[], views: number[]): number[] => {
+    const active = minutes.filter((_, i) => views[i] > 0).sort((a, b) => a - b);
+    if (active.length === 0) return [];
+    return [1, 2, 3].map((q) => active[Math.floor((active.length * q) / 4)]);
+};
+
+export const CalendarHeatmap: React.FC<CalendarHeatmapProps> = ({ calendar }) => {
+    const start = new Date(`${calendar.start}T00:00:00Z`);
+    const offset = start.getUTCDay();
+    const weeks = Math.floor((offset + calendar.views.length - 1) / 7) + 1;
+    const th = thresholds(calendar.minutes, calendar.views);
+
+    const level = (i: number): number => {
+        if (calendar.views[i] === 0) return 0;
+        return Math.min(1 + th.filter((t) => calendar.minutes[i] >= t).length, COLORS.length - 1);
+    };
+
+    return (
+        <div className="section">
+            <h3>Viewing Calendar</h3>
+            <div className="calendar">
+                <svg width={weeks * STEP} height={7 * STEP}>
+                    {calendar.views.map((v, i) => {
+                        const pos = offset + i;
+                        const date = new Date(start.getTime() + i * 86400000);
+                        return (
+                            <rect
+                                key={i}
+                                x={Math.floor(pos / 7) * STEP}
+                                y={(pos % 7) * STEP}
+                                width={CELL}
+                                height={CELL}
+                                rx={2}
+                                fill={COLORS[level(i)]}
+                            >
+                                <title>
+                                    {`${date.toISOString().slice(0, 10)}: ${v} views / ${formatMinLong(calendar.minutes[i])}`}
+                                </title>
+                            </rect>
+                        );
+                    })}
+                </svg>
+            </div>
+        </div>
+    );
+};
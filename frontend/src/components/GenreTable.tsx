@@ -20,7 +20,7 @@ export const GenreTable: React.FC<GenreTableProps> = ({ stats }) => {
                 </thead>
                 <tbody>
                     {stats.slice(0, 10).map((g) => (
-                        <tr key={g.Name}>
+                        <tr key={g.Key}>
                             <td>{g.Name}</td>
                             <td>{formatMinLong(g.DurationMin)}</td>
                             <td>{g.Share.toFixed(1)}%</td>
@@ -16,12 +16,28 @@ export interface Gap {
 }
 
 export interface GenreStat {
+    Key: string;
     Name: string;
     DurationMin: number;
     Views: number;
     Share: number;
 }
 
+export interface GenreMonth {
+    Year: number;
+    Month: number;
+    Views: number;
+    DurationMin: number;
+    Share: number;
+}
+
+// Months are aligned with the months of the recap period.
+export interface GenreTrend {
+    Key: string;
+    Name: string;
+    Months: GenreMonth[];
+}
+
 export interface Spike {
     Month: number;
     DurationMin: number;
@@ -33,6 +49,8 @@ export interface TitleStat {
     Type: string;
     DurationMin: number;
     Views: number;
+    PosterPath: string;
+    Genres: string[] | null; // genre keys
 }
 
 export interface SeriesStat {
@@ -41,12 +59,14 @@ export interface SeriesStat {
     Views: number;
     SpanStart: string;
     SpanEnd: string;
+    PosterPath: string;
 }
 
 export interface UnresolvedItem {
     Title: string;
     Type: string;
     Views: number;
+    RawTitles: string[];
 }
 
 export interface Stats {
@@ -61,6 +81,7 @@ export interface Stats {
     MonthlyStats: Record<string, UseMetric>;
     WeekdayStats: Record<string, UseMetric>;
     GenreStats: GenreStat[];
+    GenreTrends: GenreTrend[];
     GenreMonthSpike: Record<string, Spike>;
     GenreSampleMovies: Record<string, string[]>;
     TopTitlesByDuration: TitleStat[];
@@ -71,6 +92,14 @@ export interface Stats {
     UnresolvedList: UnresolvedItem[];
 }
 
+// Views[i] and minutes[i] belong to start + i days.
+export interface CompactCalendar {
+    start: string;
+    views: number[];
+    minutes: number[];
+}
+
 export interface ApiResponse {
     recap: Stats;
+    calendar: CompactCalendar;
 }